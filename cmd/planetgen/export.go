@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"stensvad-ossianst-melvinbe-project/export"
+	"stensvad-ossianst-melvinbe-project/generation"
+)
+
+/*
+runExport generates a planet without opening a window or creating an OpenGL context and
+writes the mesh to every output file given as an argument. The lakes and rivers of planets with rivers
+can be written to their own files.
+
+Usage:
+
+	planetgen export [-preset earth | -settings planet.yaml] [-seed 42] [-radius 2] [-res 200] [-water water.glb] [-rivers rivers.obj] planet.obj planet.ply planet.glb
+*/
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: export [flags] output.obj|output.ply|output.glb ...")
+		flags.PrintDefaults()
+	}
+
+	planet := addPlanetFlags(flags)
+	radius := flags.Float64("radius", 0, "radius of the planet, overrides the preset")
+	res := flags.Uint("res", 0, "resolution of the planet, overrides the preset")
+	waterPath := flags.String("water", "", "also write the mesh of the lakes and rivers to this .obj, .ply or .glb file")
+	riversPath := flags.String("rivers", "", "also write the rivers as lines to this .obj or .json file")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("no output files given")
+	}
+
+	settings, err := planet.settings()
+	if err != nil {
+		return err
+	}
+
+	// Only override the settings with flags that were actually given
+	var invalid error
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "radius":
+			if !(*radius > 0) {
+				invalid = fmt.Errorf("radius must be greater than 0")
+			}
+			settings.Shape.Radius = float32(*radius)
+		case "res":
+			if *res == 0 {
+				invalid = fmt.Errorf("res must be greater than 0")
+			}
+			settings.Shape.Res = uint32(*res)
+		}
+	})
+	if invalid != nil {
+		return invalid
+	}
+
+	// The mesh is divided by the resolution scaled by the radius, which has to give at least one triangle per face
+	if scaled := float32(settings.Shape.Res) * settings.Shape.Radius; scaled < 1 {
+		return fmt.Errorf("res times radius must be at least 1, got %g", scaled)
+	}
+
+	// Every file is checked before the planet is generated, so a failed export writes nothing
+	if (*waterPath != "" || *riversPath != "") && settings.Shape.RiverMinArea <= 0 {
+		return fmt.Errorf("the planet has no rivers or lakes, set shape.rivers.min_area in its settings")
+	}
+
+	vertices, indices, water, err := generation.GenPlanetWithWater(settings.Shape)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range flags.Args() {
+		if err := export.WriteMesh(path, vertices, indices, settings.Shape.Radius); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "wrote %d vertices and %d triangles to %s\n", len(vertices)/generation.VertexStride, len(indices)/3, path)
+	}
+
+	if *waterPath != "" {
+		if err := export.WriteMesh(*waterPath, water.WaterVertices, water.WaterIndices, settings.Shape.Radius); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "wrote %d water triangles to %s\n", len(water.WaterIndices)/3, *waterPath)
+	}
+	if *riversPath != "" {
+		if err := export.WriteRivers(*riversPath, water.Rivers, settings.Shape.Radius); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "wrote %d rivers to %s\n", len(water.Rivers), *riversPath)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+// Command planetgen generates planets without opening a window.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+// Every subcommand takes the arguments that follow its name
+var commands = map[string]func(args []string) error{
+	"export": runExport,
+	"maps":   runMaps,
+}
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		log.Fatalf("unknown command %q", os.Args[1])
+	}
+
+	if err := run(os.Args[2:]); err != nil {
+		log.Fatalf("%s failed: %v", os.Args[1], err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: planetgen <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  export    write a planet mesh to OBJ, PLY or glTF files")
+	fmt.Fprintln(os.Stderr, "  maps      write heightmaps, normal maps and land/ocean masks as PNG files")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Use planetgen <command> -h to list the flags of a command.")
+}
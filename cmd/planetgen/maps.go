@@ -0,0 +1,162 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	_ "image/png"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"stensvad-ossianst-melvinbe-project/export"
+	"stensvad-ossianst-melvinbe-project/res"
+)
+
+/*
+runMaps samples the terrain of a planet over an image grid and writes a 16-bit heightmap, a tangent space
+normal map, a land/ocean mask and an albedo texture, named after the output prefix given as an argument.
+
+Usage:
+
+	planetgen maps [-preset earth | -settings planet.yaml] [-seed 42] [-projection equirect|cube] [-size 1024] [-maps height,normal,mask,albedo] out/earth
+*/
+func runMaps(args []string) error {
+	flags := flag.NewFlagSet("maps", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: maps [flags] output-prefix")
+		fmt.Fprintln(flags.Output(), "")
+		fmt.Fprintln(flags.Output(), "Writes <prefix>_<map>.png for the equirectangular projection and")
+		fmt.Fprintln(flags.Output(), "<prefix>_<map>_<face>.png for every face (px, nx, py, ny, pz, nz) of the cube projection.")
+		flags.PrintDefaults()
+	}
+
+	planet := addPlanetFlags(flags)
+	projection := flags.String("projection", "equirect", "how the sphere is laid out: equirect (one image twice as wide as high) or cube (six square faces)")
+	size := flags.Int("size", 1024, "height of the equirectangular image, or width and height of every cube face, in pixels")
+	mapList := flags.String("maps", "height,normal,mask,albedo", "comma separated maps to write: height, normal, mask and albedo")
+	textured := flags.Bool("texture", true, "tint the albedo with the texture of the planet, like the viewer does")
+	assetPaths := flags.String("assets", os.Getenv(res.EnvVar), "folders to load the texture of the planet from before the bundled assets, separated like PATH")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected exactly one output prefix")
+	}
+	if *size <= 0 {
+		return fmt.Errorf("size must be greater than 0")
+	}
+	prefix := flags.Arg(0)
+
+	wanted := map[string]bool{}
+	for _, name := range strings.Split(*mapList, ",") {
+		name = strings.TrimSpace(name)
+		if name != "height" && name != "normal" && name != "mask" && name != "albedo" {
+			return fmt.Errorf("unknown map %q, expected height, normal, mask or albedo", name)
+		}
+		wanted[name] = true
+	}
+
+	settings, err := planet.settings()
+	if err != nil {
+		return err
+	}
+
+	var texture image.Image
+	if wanted["albedo"] && *textured {
+		texture, err = loadTexture(res.NewSearchPath(filepath.SplitList(*assetPaths)...), settings.TexturePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Every map is written to a file named after the map and, for cube maps, the face
+	var maps []export.Maps
+	var suffixes []string
+	switch *projection {
+	case "equirect":
+		equirect, err := export.BakeEquirectangular(settings.Shape, *size*2)
+		if err != nil {
+			return err
+		}
+		maps = append(maps, equirect)
+		suffixes = append(suffixes, "")
+	case "cube":
+		faces, err := export.BakeCubeMap(settings.Shape, *size)
+		if err != nil {
+			return err
+		}
+		for i := range faces {
+			maps = append(maps, faces[i])
+			suffixes = append(suffixes, "_"+export.CubeFaces[i])
+		}
+	default:
+		return fmt.Errorf("unknown projection %q, expected equirect or cube", *projection)
+	}
+
+	// Use the same height range for every face so that the faces line up
+	min, max := maps[0].HeightRange()
+	for i := range maps[1:] {
+		faceMin, faceMax := maps[i+1].HeightRange()
+		if faceMin < min {
+			min = faceMin
+		}
+		if faceMax > max {
+			max = faceMax
+		}
+	}
+
+	for i := range maps {
+		images := map[string]image.Image{}
+		if wanted["height"] {
+			images["height"] = maps[i].HeightImage(min, max)
+		}
+		if wanted["normal"] {
+			images["normal"] = maps[i].NormalImage()
+		}
+		if wanted["mask"] {
+			images["mask"] = maps[i].MaskImage()
+		}
+		if wanted["albedo"] {
+			images["albedo"] = maps[i].AlbedoImage(settings, texture)
+		}
+
+		for _, name := range []string{"height", "normal", "mask", "albedo"} {
+			img, ok := images[name]
+			if !ok {
+				continue
+			}
+			path := prefix + "_" + name + suffixes[i] + ".png"
+			if err := export.WritePNG(path, img); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "wrote %s\n", path)
+		}
+	}
+
+	if wanted["height"] {
+		fmt.Fprintf(os.Stderr, "black is %g and white is %g planet radii from the center, sea level is 1\n", min, max)
+	}
+
+	return nil
+}
+
+// Reads a texture from the "textures" folder of the assets
+func loadTexture(assets fs.FS, name string) (image.Image, error) {
+	file, err := assets.Open(path.Join("textures", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open texture: %v", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode texture %q: %v", name, err)
+	}
+
+	return img, nil
+}
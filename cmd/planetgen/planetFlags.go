@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+)
+
+// The flags every command uses to pick the planet to generate
+type planetFlags struct {
+	flags        *flag.FlagSet
+	preset       *string
+	settingsPath *string
+	seed         *int64
+}
+
+func addPlanetFlags(flags *flag.FlagSet) *planetFlags {
+	return &planetFlags{
+		flags,
+		flags.String("preset", "earth", "planet preset to generate: earth, moon or sun"),
+		flags.String("settings", "", "planet settings file (.json, .yaml or .toml) to generate instead of a preset"),
+		flags.Int64("seed", 0, "seed of the terrain, overrides the preset"),
+	}
+}
+
+// Returns the settings of the preset or settings file, with the seed flag on top if it was given.
+// Must be called after the flags have been parsed.
+func (p *planetFlags) settings() (generation.PlanetSettings, error) {
+	var settings generation.PlanetSettings
+	var err error
+	if *p.settingsPath != "" {
+		settings, err = generation.LoadPlanetSettings(*p.settingsPath)
+	} else {
+		settings, err = generation.Preset(*p.preset)
+	}
+	if err != nil {
+		return generation.PlanetSettings{}, err
+	}
+
+	// Only override the settings with flags that were actually given
+	p.flags.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			settings.Shape.Seed = *p.seed
+		}
+	})
+
+	return settings, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	_ "image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"stensvad-ossianst-melvinbe-project/renderer"
+	"stensvad-ossianst-melvinbe-project/res"
+	"stensvad-ossianst-melvinbe-project/scene"
+)
+
+// Global variables
+var windowWidth = 800 * 2
+var windowHeight = 600 * 2
+
+var scenePath = flag.String("scene", "", "scene file (.json, .yaml or .toml) describing the solar system to view, defaults to scenes/solar.yaml of the assets")
+var assetPaths = flag.String("assets", os.Getenv(res.EnvVar), "folders to load shaders, textures and scenes from before the bundled assets, separated like PATH (default $"+res.EnvVar+")")
+
+func init() {
+	// GLFW event handling must run on the main OS thread
+	runtime.LockOSThread()
+}
+
+func main() {
+	flag.Parse()
+	assets := res.NewSearchPath(filepath.SplitList(*assetPaths)...)
+	renderer.Assets = assets
+
+	var s scene.Scene
+	var err error
+	if *scenePath == "" {
+		*scenePath = "scenes/solar.yaml"
+		s, err = scene.LoadFS(assets, *scenePath)
+	} else {
+		s, err = scene.Load(*scenePath)
+	}
+	if err != nil {
+		log.Fatalln("failed to load scene:", err)
+	}
+
+	if err := glfw.Init(); err != nil {
+		log.Fatalln("failed to initialize glfw:", err)
+	}
+	defer glfw.Terminate()
+
+	glfw.WindowHint(glfw.Resizable, glfw.False)
+	glfw.WindowHint(glfw.ContextVersionMajor, 4)
+	glfw.WindowHint(glfw.ContextVersionMinor, 1)
+	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	window, err := glfw.CreateWindow(windowWidth, windowHeight, "Planets!", nil, nil)
+	if err != nil {
+		log.Fatalln("failed to create a window with an OpenGL 4.1 core context:", err)
+	}
+	window.MakeContextCurrent()
+
+	fbWidth, fbHeight := window.GetFramebufferSize()
+
+	// Initialize Glow
+	if err := gl.Init(); err != nil {
+		log.Fatalln("failed to initialize OpenGL:", err)
+	}
+
+	version := gl.GoStr(gl.GetString(gl.VERSION))
+	fmt.Println("OpenGL version", version)
+
+	// Configure global settings
+	renderer.ConfigureGL()
+
+	// Create the atmospheres and the skybox of the scene, the planets are generated in the background
+	cam := renderer.NewCamera(windowWidth, windowHeight, mgl32.Vec3(s.Camera.Position))
+	system, err := renderer.LoadSolarSystemInBackground(&s, &cam, fbWidth, fbHeight, func(body string, fraction float32) {
+		log.Printf("generating %s: %.0f%%", body, fraction*100)
+	})
+	if err != nil {
+		log.Fatalf("failed to load %s: %v", *scenePath, err)
+	}
+	defer system.Close()
+
+	for !window.ShouldClose() {
+		// Update:
+		cam.Inputs(window)
+		if err := system.Upload(&cam); err != nil {
+			log.Println("failed to load planet:", err)
+		}
+
+		// Draw:
+		system.Draw(&cam)
+
+		// Maintenance
+		window.SwapBuffers()
+		glfw.PollEvents()
+	}
+}
@@ -0,0 +1,165 @@
+package export
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+)
+
+/*
+AlbedoImage colors the terrain the same way as "planet.shader", from the height and steepness of every pixel,
+without any lighting. Below sea level, planets with an ocean are colored with the water color.
+Planets with a climate color flat land by its biome, and planets with ice are covered in the ice color, like in the viewer.
+
+Parameters:
+- settings: the planet settings with the colors and texture scale of the planet
+- texture: the texture of the planet that tints the colors, or nil to leave the colors untinted
+
+Returns:
+- img: the albedo texture
+
+Example usage:
+
+	settings := generation.DefaultEarth()
+	maps, err := export.BakeEquirectangular(settings.Shape, 2048)
+	img := maps.AlbedoImage(settings, nil)
+*/
+func (m *Maps) AlbedoImage(settings generation.PlanetSettings, texture image.Image) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, m.Width, m.Height))
+
+	toByte := func(v float32) uint8 {
+		return uint8(math.Round(float64(mgl32.Clamp(v, 0.0, 1.0)) * 255))
+	}
+
+	ice := generation.GenIce(m.Directions, m.Heights, settings.Shape)
+
+	for i, h := range m.Heights {
+		var col mgl32.Vec3
+		if settings.HasOcean && h < 1.0 {
+			col = settings.Colors.WaterCol
+			if ice != nil {
+				// Sea ice floats on the surface, so it is flat
+				col = lerp(col, settings.Colors.IceCol, iceCover(ice[i], 1.0))
+			}
+		} else {
+			// The flatness is the z component as the normals are in tangent space
+			col = heightColor(settings.Colors, h-1.0, m.Normals[i].Z())
+			if m.Biomes != nil {
+				col = landBiomeColor(col, m.Biomes[i], h-1.0, m.Normals[i].Z())
+			}
+			if ice != nil {
+				col = lerp(col, settings.Colors.IceCol, iceCover(ice[i], m.Normals[i].Z()))
+			}
+
+			if texture != nil {
+				// Like the shader, the texture only tints the colors slightly
+				texColor := triplanarSample(texture, m.Directions[i].Mul(h), m.Directions[i], settings.TextureScale)
+				col = mgl32.Vec3{col.X() * (0.7 + texColor.X()*0.3), col.Y() * (0.7 + texColor.Y()*0.3), col.Z() * (0.7 + texColor.Z()*0.3)}
+			}
+		}
+
+		img.SetNRGBA(i%m.Width, i/m.Width, color.NRGBA{toByte(col.X()), toByte(col.Y()), toByte(col.Z()), 255})
+	}
+
+	return img
+}
+
+// Calculates the color of the terrain from its height above sea level and flatness, as in "planet.shader"
+func heightColor(c generation.PlanetColors, height, flatness float32) mgl32.Vec3 {
+	col := c.ShoreColLow
+
+	col = lerp(col, c.ShoreColHigh, (height-0.01)/(0.02-0.01))
+
+	col = lerp(col, c.FlatColLow, (height-0.02)/(0.04-0.03))
+
+	col = lerp(col, c.FlatColHigh, (height-0.04)/(0.05-0.04))
+
+	// Color less flat areas as steep color
+	col = lerp(col, c.SteepColLow, (float32(math.Max(float64(height-0.02), 0))*5.0)*(0.9-flatness)*15.0)
+
+	col = lerp(col, c.SteepColLow, (height-0.05)/0.05)
+
+	col = lerp(col, c.SteepColHigh, (height-0.12)*50.0)
+
+	return col
+}
+
+// Colors flat land by its biome, but leaves shores and steep slopes with their height colors, as in "planet.shader"
+func landBiomeColor(col mgl32.Vec3, biome generation.Biome, height, flatness float32) mgl32.Vec3 {
+	if biome < generation.IceBiome {
+		return col
+	}
+
+	k := mgl32.Clamp((height-0.01)/(0.02-0.01), 0.0, 1.0)
+	k *= mgl32.Clamp((flatness-0.8)*10.0, 0.0, 1.0)
+	return lerp(col, biome.Color(), k)
+}
+
+// How much of the ground is colored by its ice, as in "planet.shader". Steep slopes do not hold snow.
+func iceCover(ice, flatness float32) float32 {
+	return ice * mgl32.Clamp((flatness-0.6)*5.0, 0.0, 1.0)
+}
+
+// Linearly interpolates between two colors, with k clamped to [0, 1]
+func lerp(a, b mgl32.Vec3, k float32) mgl32.Vec3 {
+	k = mgl32.Clamp(k, 0.0, 1.0)
+	return a.Mul(1.0 - k).Add(b.Mul(k))
+}
+
+// Maps a texture to six sides of the planet, as triplanarTexture in "planet.shader"
+func triplanarSample(texture image.Image, pos, normal mgl32.Vec3, scale float32) mgl32.Vec3 {
+	fract := func(v float32) float32 {
+		return v - float32(math.Floor(float64(v)))
+	}
+
+	// Sample texture colors in three directions
+	colX := bilinearSample(texture, fract(pos.Z()*scale), fract(pos.Y()*scale))
+	colY := bilinearSample(texture, fract(pos.X()*scale), fract(pos.Z()*scale))
+	colZ := bilinearSample(texture, fract(pos.X()*scale), fract(pos.Y()*scale))
+
+	// Calculate how much every color will contribute to final color
+	weight := mgl32.Vec3{
+		float32(math.Sqrt(math.Abs(float64(normal.X())))),
+		float32(math.Sqrt(math.Abs(float64(normal.Y())))),
+		float32(math.Sqrt(math.Abs(float64(normal.Z())))),
+	}
+	weight = weight.Mul(1.0 / (weight.X() + weight.Y() + weight.Z()))
+
+	return colX.Mul(weight.X()).Add(colY.Mul(weight.Y())).Add(colZ.Mul(weight.Z()))
+}
+
+// Samples a texture at the texture coordinates u and v like OpenGL with linear filtering and edges clamped.
+// As in OpenGL, v = 0 is the first row of the image.
+func bilinearSample(texture image.Image, u, v float32) mgl32.Vec3 {
+	bounds := texture.Bounds()
+	x := u*float32(bounds.Dx()) - 0.5
+	y := v*float32(bounds.Dy()) - 0.5
+
+	x0, y0 := int(math.Floor(float64(x))), int(math.Floor(float64(y)))
+	fx, fy := x-float32(x0), y-float32(y0)
+
+	texel := func(x, y int) mgl32.Vec3 {
+		x = bounds.Min.X + clampInt(x, 0, bounds.Dx()-1)
+		y = bounds.Min.Y + clampInt(y, 0, bounds.Dy()-1)
+		r, g, b, _ := texture.At(x, y).RGBA()
+		return mgl32.Vec3{float32(r) / 0xffff, float32(g) / 0xffff, float32(b) / 0xffff}
+	}
+
+	top := lerp(texel(x0, y0), texel(x0+1, y0), fx)
+	bottom := lerp(texel(x0, y0+1), texel(x0+1, y0+1), fx)
+	return lerp(top, bottom, fy)
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
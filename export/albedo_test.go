@@ -0,0 +1,151 @@
+package export
+
+import (
+	"image"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+)
+
+// Checks that a pixel of an albedo image has a color, give or take rounding
+func checkAlbedo(t *testing.T, name string, img *image.NRGBA, x, y int, want mgl32.Vec3) {
+	t.Helper()
+
+	got := img.NRGBAAt(x, y)
+	for i, c := range []uint8{got.R, got.G, got.B} {
+		if d := float32(c)/255 - want[i]; d > 1.0/255 || d < -1.0/255 || got.A != 255 {
+			t.Errorf("%s is %v, want %v", name, got, want)
+			return
+		}
+	}
+}
+
+func TestAlbedoImage(t *testing.T) {
+	settings := generation.DefaultEarth()
+	settings.Shape.HasIce = false
+	colors := settings.Colors
+
+	// Sea, a low shore, a mountain top and a cliff, all on the equator
+	equator := mgl32.Vec3{0, 0, 1}
+	maps := Maps{
+		4, 1,
+		[]float32{0.99, 1.005, 1.2, 1.2},
+		[]mgl32.Vec3{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {1, 0, 0}},
+		[]mgl32.Vec3{equator, equator, equator, equator},
+		nil,
+	}
+
+	img := maps.AlbedoImage(settings, nil)
+	if img.Bounds() != image.Rect(0, 0, 4, 1) {
+		t.Fatalf("albedo is %v, want 4x1", img.Bounds())
+	}
+	checkAlbedo(t, "sea", img, 0, 0, colors.WaterCol)
+	checkAlbedo(t, "shore", img, 1, 0, colors.ShoreColLow)
+	checkAlbedo(t, "mountain top", img, 2, 0, colors.SteepColHigh)
+	checkAlbedo(t, "cliff", img, 3, 0, colors.SteepColHigh)
+
+	// Without an ocean the sea floor is colored like land
+	settings.HasOcean = false
+	checkAlbedo(t, "dry sea floor", maps.AlbedoImage(settings, nil), 0, 0, colors.ShoreColLow)
+}
+
+func TestAlbedoIce(t *testing.T) {
+	settings := generation.DefaultEarth()
+	settings.Shape.HasIce = true
+	colors := settings.Colors
+
+	// Frozen sea, flat ground and a cliff at the north pole
+	pole := mgl32.Vec3{0, 1, 0}
+	maps := Maps{
+		3, 1,
+		[]float32{0.99, 1.005, 1.005},
+		[]mgl32.Vec3{{0, 0, 1}, {0, 0, 1}, {1, 0, 0}},
+		[]mgl32.Vec3{pole, pole, pole},
+		nil,
+	}
+
+	img := maps.AlbedoImage(settings, nil)
+	checkAlbedo(t, "sea ice", img, 0, 0, colors.IceCol)
+	checkAlbedo(t, "snow", img, 1, 0, colors.IceCol)
+	checkAlbedo(t, "cliff", img, 2, 0, colors.ShoreColLow)
+}
+
+func TestAlbedoTexture(t *testing.T) {
+	settings := generation.DefaultEarth()
+	settings.Shape.HasIce = false
+	colors := settings.Colors
+
+	equator := mgl32.Vec3{0, 0, 1}
+	maps := Maps{
+		2, 1,
+		[]float32{0.99, 1.005},
+		[]mgl32.Vec3{{0, 0, 1}, {0, 0, 1}},
+		[]mgl32.Vec3{equator, equator},
+		nil,
+	}
+
+	// A white texture leaves the colors as they are, a black one darkens the land but not the sea
+	for _, c := range []struct {
+		gray  uint8
+		scale float32
+	}{{255, 1.0}, {0, 0.7}} {
+		texture := image.NewGray(image.Rect(0, 0, 4, 4))
+		for i := range texture.Pix {
+			texture.Pix[i] = c.gray
+		}
+		img := maps.AlbedoImage(settings, texture)
+
+		checkAlbedo(t, "sea", img, 0, 0, colors.WaterCol)
+		checkAlbedo(t, "tinted shore", img, 1, 0, colors.ShoreColLow.Mul(c.scale))
+	}
+}
+
+func TestAlbedoBiomes(t *testing.T) {
+	settings := generation.DefaultEarth()
+	settings.Shape.Res = 16
+	settings.Shape.HasIce = false
+	settings.Shape.HasClimate = true
+
+	// The climate is found on a grid as detailed as the mesh, which this bake is the same as
+	maps := bakeEquirectangular(t, settings.Shape, 64)
+	if len(maps.Biomes) != len(maps.Heights) {
+		t.Fatalf("maps have %d biomes, want %d", len(maps.Biomes), len(maps.Heights))
+	}
+	found := map[generation.Biome]int{}
+	for i, h := range maps.Heights {
+		if (maps.Biomes[i] == generation.OceanBiome) != (h < 1.0) {
+			t.Fatalf("pixel %d at height %v has the biome %v", i, h, maps.Biomes[i])
+		}
+		found[maps.Biomes[i]]++
+	}
+	if found[generation.NoBiome] != 0 || len(found) < 4 {
+		t.Errorf("earth has the biomes %v, want oceans and more", found)
+	}
+
+	// Flat land above the shores is colored by its biome
+	for i := range maps.Heights {
+		maps.Heights[i] = 1.03
+		maps.Normals[i] = mgl32.Vec3{0, 0, 1}
+	}
+	img := maps.AlbedoImage(settings, nil)
+	for i, biome := range maps.Biomes {
+		if biome >= generation.IceBiome {
+			checkAlbedo(t, biome.String(), img, i%64, i/64, biome.Color())
+		}
+	}
+
+	// The faces of a cube map get their biomes from the same climate
+	faces := bakeCubeMap(t, settings.Shape, 8)
+	for face, m := range faces {
+		if len(m.Biomes) != len(m.Heights) {
+			t.Errorf("face %s has %d biomes, want %d", CubeFaces[face], len(m.Biomes), len(m.Heights))
+		}
+	}
+
+	settings.Shape.HasClimate = false
+	if maps := bakeEquirectangular(t, settings.Shape, 16); maps.Biomes != nil {
+		t.Errorf("planet without a climate got %d biomes", len(maps.Biomes))
+	}
+}
@@ -0,0 +1,315 @@
+package export
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+)
+
+// Names of the six faces of a cube map, in the OpenGL order +X, -X, +Y, -Y, +Z, -Z
+var CubeFaces = [6]string{"px", "nx", "py", "ny", "pz", "nz"}
+
+// Maps holds the terrain of a planet sampled over a grid of directions, one per pixel
+type Maps struct {
+	Width  int
+	Height int
+
+	// Heights holds the distance from the center of the planet to the surface at every pixel, row by row,
+	// where 1.0 is sea level
+	Heights []float32
+	// Normals holds the surface normal at every pixel in tangent space: x points right in the image,
+	// y points up in the image and z points away from the planet
+	Normals []mgl32.Vec3
+	// Directions holds the point on the unit sphere of every pixel
+	Directions []mgl32.Vec3
+	// Biomes holds the biome of every pixel, nil if the planet has no climate
+	Biomes []generation.Biome
+}
+
+/*
+BakeEquirectangular samples the terrain of a planet over an equirectangular grid twice as wide as it is high.
+The left edge of the image is at -180 degrees longitude, the top edge is the north pole (+Y).
+
+Parameters:
+- shape: the planet shape struct containing a recipe for the planets shape
+- width: the width of the grid in pixels
+
+Returns:
+- maps: the heights and normals of every pixel
+- err: an error if the terrain graph of the shape is invalid
+
+Example usage:
+
+	maps, err := export.BakeEquirectangular(generation.DefaultEarth().Shape, 2048)
+	min, max := maps.HeightRange()
+	err = export.WritePNG("earth_height.png", maps.HeightImage(min, max))
+*/
+func BakeEquirectangular(shape generation.PlanetShape, width int) (Maps, error) {
+	terrain, err := generation.CompileTerrain(shape)
+	if err != nil {
+		return Maps{}, err
+	}
+
+	biomes := bakeBiomes(shape, terrain)
+	return bakeGrid(terrain, width, width/2, equirectangularDirection(width, width/2), biomes), nil
+}
+
+// Maps pixel coordinates of an equirectangular grid to a point on the unit sphere
+func equirectangularDirection(width, height int) func(x, y float64) mgl32.Vec3 {
+	return func(x, y float64) mgl32.Vec3 {
+		longitude := x/float64(width)*2.0*math.Pi - math.Pi
+		latitude := math.Pi/2.0 - y/float64(height)*math.Pi
+		return mgl32.Vec3{
+			float32(math.Cos(latitude) * math.Sin(longitude)),
+			float32(math.Sin(latitude)),
+			float32(math.Cos(latitude) * math.Cos(longitude)),
+		}
+	}
+}
+
+/*
+BakeCubeMap samples the terrain of a planet over the six faces of a cube map, laid out like the faces of
+an OpenGL cube map texture
+
+Parameters:
+- shape: the planet shape struct containing a recipe for the planets shape
+- size: the width and height of every face in pixels
+
+Returns:
+- faces: the heights and normals of every face, in the order of CubeFaces
+- err: an error if the terrain graph of the shape is invalid
+
+Example usage:
+
+	faces, err := export.BakeCubeMap(generation.DefaultMoon().Shape, 512)
+*/
+func BakeCubeMap(shape generation.PlanetShape, size int) ([6]Maps, error) {
+	var faces [6]Maps
+
+	// Every face is sampled from the same terrain, compiled once
+	terrain, err := generation.CompileTerrain(shape)
+	if err != nil {
+		return faces, err
+	}
+
+	// Every face gets its biomes from the same climate, so they match at the edges
+	biomes := bakeBiomes(shape, terrain)
+
+	for face := range faces {
+		face := face
+		direction := func(x, y float64) mgl32.Vec3 {
+			s := float32(2.0*x/float64(size) - 1.0)
+			t := float32(2.0*y/float64(size) - 1.0)
+			return cubeFaceDirection(face, s, t).Normalize()
+		}
+		faces[face] = bakeGrid(terrain, size, size, direction, biomes)
+	}
+
+	return faces, nil
+}
+
+// Returns the point on the unit cube at the coordinates s and t, from -1 to 1, of a cube map face
+func cubeFaceDirection(face int, s, t float32) mgl32.Vec3 {
+	switch face {
+	case 0:
+		return mgl32.Vec3{1, -t, -s}
+	case 1:
+		return mgl32.Vec3{-1, -t, s}
+	case 2:
+		return mgl32.Vec3{s, 1, t}
+	case 3:
+		return mgl32.Vec3{s, -1, -t}
+	case 4:
+		return mgl32.Vec3{s, -t, 1}
+	default:
+		return mgl32.Vec3{-s, -t, -1}
+	}
+}
+
+// Samples the terrain at every pixel of a grid, plus a border of one pixel to calculate normals at the edges.
+// direction maps pixel coordinates, where pixel centers are at x+0.5 and y+0.5, to a point on the unit sphere,
+// and biomes finds the biome in a direction, or is nil if the planet has no climate.
+func bakeGrid(terrain *generation.Terrain, width, height int, direction func(x, y float64) mgl32.Vec3, biomes func(direction mgl32.Vec3) generation.Biome) Maps {
+	stride := width + 2
+
+	directions := make([]mgl32.Vec3, stride*(height+2))
+	for y := -1; y <= height; y++ {
+		for x := -1; x <= width; x++ {
+			directions[(y+1)*stride+x+1] = direction(float64(x)+0.5, float64(y)+0.5)
+		}
+	}
+
+	heights := terrain.Heights(directions)
+
+	// Points on the surface of the planet
+	surface := func(x, y int) mgl32.Vec3 {
+		i := (y+1)*stride + x + 1
+		return directions[i].Mul(heights[i])
+	}
+
+	maps := Maps{width, height, make([]float32, width*height), make([]mgl32.Vec3, width*height), make([]mgl32.Vec3, width*height), nil}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := (y+1)*stride + x + 1
+			up := directions[i]
+
+			// Tangent space of the pixel on the sphere
+			tangent := directions[i+1].Sub(directions[i-1])
+			tangent = tangent.Sub(up.Mul(tangent.Dot(up))).Normalize()
+			bitangent := up.Cross(tangent)
+
+			// Normal of the surface from the neighbouring pixels
+			dx := surface(x+1, y).Sub(surface(x-1, y))
+			dy := surface(x, y-1).Sub(surface(x, y+1))
+			normal := dx.Cross(dy).Normalize()
+
+			maps.Heights[y*width+x] = heights[i]
+			maps.Normals[y*width+x] = mgl32.Vec3{normal.Dot(tangent), normal.Dot(bitangent), normal.Dot(up)}
+			maps.Directions[y*width+x] = up
+		}
+	}
+
+	if biomes != nil {
+		maps.Biomes = make([]generation.Biome, width*height)
+		for i, direction := range maps.Directions {
+			maps.Biomes[i] = biomes(direction)
+		}
+	}
+
+	return maps
+}
+
+// The climate is found on a mesh, as the wind carries moisture from vertex to vertex. Finds the climate over
+// an equirectangular grid joined into triangles, as detailed as the planet mesh with as many points around the equator,
+// and returns the biome of the grid point closest to a direction. Returns nil if the planet has no climate.
+func bakeBiomes(shape generation.PlanetShape, terrain *generation.Terrain) func(direction mgl32.Vec3) generation.Biome {
+	if !shape.HasClimate {
+		return nil
+	}
+
+	width, height := int(shape.Res)*4, int(shape.Res)*2
+	direction := equirectangularDirection(width, height)
+
+	directions := make([]mgl32.Vec3, width*height)
+	for i := range directions {
+		directions[i] = direction(float64(i%width)+0.5, float64(i/width)+0.5)
+	}
+	heights := terrain.Heights(directions)
+
+	points := make([]mgl32.Vec3, len(directions))
+	for i, h := range heights {
+		points[i] = directions[i].Mul(h)
+	}
+
+	// The grid wraps around at the left and right edges
+	indices := make([]uint32, 0, width*(height-1)*6)
+	for y := 0; y < height-1; y++ {
+		for x := 0; x < width; x++ {
+			a := uint32(y*width + x)
+			b := uint32(y*width + (x+1)%width)
+			c, d := a+uint32(width), b+uint32(width)
+			indices = append(indices, a, c, b, b, c, d)
+		}
+	}
+
+	climate := generation.GenClimate(points, indices, shape)
+
+	return func(direction mgl32.Vec3) generation.Biome {
+		longitude := math.Atan2(float64(direction.X()), float64(direction.Z()))
+		latitude := math.Asin(float64(mgl32.Clamp(direction.Y(), -1.0, 1.0)))
+
+		x := int(math.Floor((longitude + math.Pi) / (2.0 * math.Pi) * float64(width)))
+		y := int(math.Floor((math.Pi/2.0 - latitude) / math.Pi * float64(height)))
+		return climate.Biomes[clampInt(y, 0, height-1)*width+(x%width+width)%width]
+	}
+}
+
+// HeightRange returns the lowest and highest height of the maps
+func (m *Maps) HeightRange() (min, max float32) {
+	min, max = float32(math.Inf(1)), float32(math.Inf(-1))
+	for _, h := range m.Heights {
+		if h < min {
+			min = h
+		}
+		if h > max {
+			max = h
+		}
+	}
+	return min, max
+}
+
+/*
+HeightImage converts the heights to a 16-bit grayscale image, where black is min and white is max
+
+Parameters:
+- min: the height that becomes black
+- max: the height that becomes white
+
+Returns:
+- img: the heightmap
+*/
+func (m *Maps) HeightImage(min, max float32) *image.Gray16 {
+	img := image.NewGray16(image.Rect(0, 0, m.Width, m.Height))
+
+	scale := float32(0.0)
+	if max > min {
+		scale = 1.0 / (max - min)
+	}
+
+	for i, h := range m.Heights {
+		v := mgl32.Clamp((h-min)*scale, 0.0, 1.0)
+		img.SetGray16(i%m.Width, i/m.Width, color.Gray16{uint16(math.Round(float64(v) * 0xffff))})
+	}
+
+	return img
+}
+
+// NormalImage converts the normals to a tangent space normal map, with every component mapped from [-1, 1] to [0, 255]
+func (m *Maps) NormalImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, m.Width, m.Height))
+
+	toByte := func(v float32) uint8 {
+		return uint8(math.Round(float64(mgl32.Clamp(v*0.5+0.5, 0.0, 1.0)) * 255))
+	}
+
+	for i, n := range m.Normals {
+		img.SetNRGBA(i%m.Width, i/m.Width, color.NRGBA{toByte(n.X()), toByte(n.Y()), toByte(n.Z()), 255})
+	}
+
+	return img
+}
+
+// MaskImage converts the heights to a mask where land is white and ocean, everything below sea level, is black
+func (m *Maps) MaskImage() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, m.Width, m.Height))
+
+	for i, h := range m.Heights {
+		if h >= 1.0 {
+			img.SetGray(i%m.Width, i/m.Width, color.Gray{255})
+		}
+	}
+
+	return img
+}
+
+// WritePNG encodes an image as a PNG file
+func WritePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,183 @@
+package export
+
+import (
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+)
+
+// Bakes the maps of a shape whose terrain graph is valid
+func bakeEquirectangular(t *testing.T, shape generation.PlanetShape, width int) Maps {
+	t.Helper()
+
+	maps, err := BakeEquirectangular(shape, width)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return maps
+}
+
+// Bakes the cube map of a shape whose terrain graph is valid
+func bakeCubeMap(t *testing.T, shape generation.PlanetShape, size int) [6]Maps {
+	t.Helper()
+
+	faces, err := BakeCubeMap(shape, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return faces
+}
+
+func TestBakeEquirectangular(t *testing.T) {
+	shape := generation.DefaultEarth().Shape
+	maps := bakeEquirectangular(t, shape, 64)
+
+	if maps.Width != 64 || maps.Height != 32 {
+		t.Fatalf("maps are %dx%d, want 64x32", maps.Width, maps.Height)
+	}
+	if len(maps.Heights) != 64*32 || len(maps.Normals) != 64*32 || len(maps.Directions) != 64*32 {
+		t.Fatalf("maps have %d heights, %d normals and %d directions, want %d", len(maps.Heights), len(maps.Normals), len(maps.Directions), 64*32)
+	}
+
+	// The top row is next to the north pole and the bottom row next to the south pole
+	if maps.Directions[0].Y() < 0.99 || maps.Directions[len(maps.Directions)-1].Y() > -0.99 {
+		t.Errorf("first and last pixels point at %v and %v, want the poles", maps.Directions[0], maps.Directions[len(maps.Directions)-1])
+	}
+
+	// Every pixel is the terrain in its direction
+	heights, err := generation.GenHeights(maps.Directions, shape)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range heights {
+		if math.Abs(float64(maps.Directions[i].Len()-1)) > 1e-5 {
+			t.Fatalf("direction %d is %v, want a unit vector", i, maps.Directions[i])
+		}
+		if heights[i] != maps.Heights[i] {
+			t.Fatalf("height %d is %v, want %v", i, maps.Heights[i], heights[i])
+		}
+	}
+}
+
+func TestBakeSphere(t *testing.T) {
+	// A planet without terrain is a sphere at sea level, so every normal points straight out
+	shape := generation.DefaultEarth().Shape
+	shape.Amplitude = 0
+
+	maps := []Maps{bakeEquirectangular(t, shape, 32)}
+	faces := bakeCubeMap(t, shape, 16)
+	maps = append(maps, faces[:]...)
+
+	for _, m := range maps {
+		for i := range m.Heights {
+			if m.Heights[i] != 1 {
+				t.Fatalf("height %d is %v, want 1", i, m.Heights[i])
+			}
+			if m.Normals[i].Sub(mgl32.Vec3{0, 0, 1}).Len() > 1e-2 {
+				t.Fatalf("normal %d is %v, want (0, 0, 1)", i, m.Normals[i])
+			}
+		}
+	}
+}
+
+func TestBakeCubeMap(t *testing.T) {
+	faces := bakeCubeMap(t, generation.DefaultMoon().Shape, 16)
+
+	// The middle of every face points along its axis, in the order of CubeFaces
+	axes := []mgl32.Vec3{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}}
+	for face, m := range faces {
+		if m.Width != 16 || m.Height != 16 {
+			t.Fatalf("face %s is %dx%d, want 16x16", CubeFaces[face], m.Width, m.Height)
+		}
+		middle := m.Directions[8*16+8].Add(m.Directions[7*16+7]).Normalize()
+		if middle.Sub(axes[face]).Len() > 1e-3 {
+			t.Errorf("face %s points at %v, want %v", CubeFaces[face], middle, axes[face])
+		}
+	}
+}
+
+func TestBakeInvalidTerrain(t *testing.T) {
+	shape := generation.DefaultEarth().Shape
+	shape.Terrain = []generation.TerrainNode{{Name: "a", Type: "plateau"}}
+
+	if _, err := BakeEquirectangular(shape, 16); err == nil {
+		t.Error("equirectangular bake accepted an invalid terrain")
+	}
+	if _, err := BakeCubeMap(shape, 8); err == nil {
+		t.Error("cube map bake accepted an invalid terrain")
+	}
+}
+
+func TestMapImages(t *testing.T) {
+	maps := Maps{
+		4, 1,
+		[]float32{0.9, 1.0, 1.05, 1.1},
+		[]mgl32.Vec3{{0, 0, 1}, {0, 0, 1}, {1, 0, 0}, {-1, 0, 0}},
+		make([]mgl32.Vec3, 4),
+		nil,
+	}
+
+	min, max := maps.HeightRange()
+	if min != 0.9 || max != 1.1 {
+		t.Fatalf("height range is %v to %v, want 0.9 to 1.1", min, max)
+	}
+	heights := maps.HeightImage(min, max)
+	if heights.Gray16At(0, 0).Y != 0 || heights.Gray16At(3, 0).Y != 0xffff {
+		t.Errorf("lowest and highest pixels are %v and %v, want black and white", heights.Gray16At(0, 0), heights.Gray16At(3, 0))
+	}
+
+	// Land from sea level up is white
+	mask := maps.MaskImage()
+	for x, want := range []uint8{0, 255, 255, 255} {
+		if got := mask.GrayAt(x, 0).Y; got != want {
+			t.Errorf("mask pixel %d is %d, want %d", x, got, want)
+		}
+	}
+
+	normals := maps.NormalImage()
+	if c := normals.NRGBAAt(0, 0); c.R != 128 || c.G != 128 || c.B != 255 {
+		t.Errorf("flat normal is %v, want (128, 128, 255)", c)
+	}
+	if c := normals.NRGBAAt(3, 0); c.R != 0 || c.B != 128 {
+		t.Errorf("normal pointing left is %v, want (0, 128, 128)", c)
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	maps := bakeEquirectangular(t, generation.DefaultMoon().Shape, 32)
+	min, max := maps.HeightRange()
+	want := maps.HeightImage(min, max)
+
+	path := filepath.Join(t.TempDir(), "height.png")
+	if err := WritePNG(path, want); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Heightmaps keep all 16 bits
+	got, ok := img.(*image.Gray16)
+	if !ok {
+		t.Fatalf("heightmap was read back as %T, want *image.Gray16", img)
+	}
+	for i := range want.Pix {
+		if got.Pix[i] != want.Pix[i] {
+			t.Fatalf("byte %d of the heightmap changed from %d to %d", i, want.Pix[i], got.Pix[i])
+		}
+	}
+}
@@ -0,0 +1,374 @@
+// Package export writes generated planets to files that can be used by other tools.
+package export
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+)
+
+// Every vertex from GenPlanet is a position(3 floats), a normal(3 floats), a biome(1 float) and its ice(1 float)
+const vertexStride = generation.VertexStride
+
+// Where the biome and the ice are in a vertex
+const (
+	biomeOffset = 6
+	iceOffset   = 7
+)
+
+// Reports if any vertex has a biome, planets without a climate are written without biome colors
+func hasBiomes(vertices []float32) bool {
+	for i := biomeOffset; i < len(vertices); i += vertexStride {
+		if generation.Biome(vertices[i]) != generation.NoBiome {
+			return true
+		}
+	}
+	return false
+}
+
+// The color of the biome of a vertex
+func biomeColor(v []float32) [3]float32 {
+	col := generation.Biome(v[biomeOffset]).Color()
+	return [3]float32{col.X(), col.Y(), col.Z()}
+}
+
+/*
+WriteMesh writes the vertices and indices of a planet to a file. The format is picked from the
+file extension: ".obj" for Wavefront OBJ, ".ply" for binary PLY and ".glb" for binary glTF 2.0.
+Planets with a climate get the colors of their biomes as vertex colors, and PLY and glTF files also get the biomes
+and how much of every vertex is covered in ice.
+
+Parameters:
+- path: the file to write to
+- vertices: the vertices of the planet, as returned by generation.GenPlanet
+- indices: the indices of the triangles of the planet, as returned by generation.GenPlanet
+- scale: what every vertex position is multiplied by, usually the planet radius
+
+Example usage:
+
+	vertices, indices, err := generation.GenPlanet(earthSettings.Shape)
+	err = export.WriteMesh("earth.glb", vertices, indices, earthSettings.Shape.Radius)
+*/
+func WriteMesh(path string, vertices []float32, indices []uint32, scale float32) error {
+	var write func(io.Writer, []float32, []uint32, float32) error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".obj":
+		write = writeOBJ
+	case ".ply":
+		write = writePLY
+	case ".glb":
+		write = writeGLB
+	default:
+		return fmt.Errorf("unsupported mesh format %q, expected .obj, .ply or .glb", filepath.Ext(path))
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	if err := write(w, vertices, indices, scale); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %q: %v", path, err)
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Writes the mesh as a Wavefront OBJ file with positions, normals and 1-indexed faces.
+// Biome colors are written after the positions, which most tools read as vertex colors.
+func writeOBJ(w io.Writer, vertices []float32, indices []uint32, scale float32) error {
+	numVertices := len(vertices) / vertexStride
+	colored := hasBiomes(vertices)
+
+	fmt.Fprintf(w, "# Planet generator mesh\n# %d vertices, %d triangles\n", numVertices, len(indices)/3)
+
+	for i := 0; i < numVertices; i++ {
+		v := vertices[i*vertexStride : (i+1)*vertexStride]
+		if colored {
+			col := biomeColor(v)
+			fmt.Fprintf(w, "v %g %g %g %g %g %g\n", v[0]*scale, v[1]*scale, v[2]*scale, col[0], col[1], col[2])
+		} else {
+			fmt.Fprintf(w, "v %g %g %g\n", v[0]*scale, v[1]*scale, v[2]*scale)
+		}
+	}
+	for i := 0; i < numVertices; i++ {
+		v := vertices[i*vertexStride : (i+1)*vertexStride]
+		fmt.Fprintf(w, "vn %g %g %g\n", v[3], v[4], v[5])
+	}
+
+	// OBJ indices start at 1 and every corner references both a position and a normal
+	for i := 0; i+2 < len(indices); i += 3 {
+		a, b, c := indices[i]+1, indices[i+1]+1, indices[i+2]+1
+		_, err := fmt.Fprintf(w, "f %d//%d %d//%d %d//%d\n", a, a, b, b, c, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Writes the mesh as a little endian binary PLY file
+func writePLY(w io.Writer, vertices []float32, indices []uint32, scale float32) error {
+	numVertices := len(vertices) / vertexStride
+	colored := hasBiomes(vertices)
+
+	colors := ""
+	if colored {
+		colors = "property uchar red\nproperty uchar green\nproperty uchar blue\n"
+	}
+
+	header := "ply\n" +
+		"format binary_little_endian 1.0\n" +
+		"comment Planet generator mesh\n" +
+		fmt.Sprintf("element vertex %d\n", numVertices) +
+		"property float x\nproperty float y\nproperty float z\n" +
+		"property float nx\nproperty float ny\nproperty float nz\n" +
+		"property float biome\nproperty float ice\n" +
+		colors +
+		fmt.Sprintf("element face %d\n", len(indices)/3) +
+		"property list uchar uint vertex_indices\n" +
+		"end_header\n"
+
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	// Positions are scaled, normals, biomes and ice are written as they are
+	buf := make([]byte, vertexStride*4, vertexStride*4+3)
+	if colored {
+		buf = buf[:vertexStride*4+3]
+	}
+	for i := 0; i < numVertices; i++ {
+		for k := 0; k < vertexStride; k++ {
+			value := vertices[i*vertexStride+k]
+			if k < 3 {
+				value *= scale
+			}
+			binary.LittleEndian.PutUint32(buf[k*4:], math.Float32bits(value))
+		}
+		if colored {
+			col := biomeColor(vertices[i*vertexStride:])
+			for k := range col {
+				buf[vertexStride*4+k] = uint8(math.Round(float64(col[k]) * 255))
+			}
+		}
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+
+	face := make([]byte, 1+3*4)
+	face[0] = 3
+	for i := 0; i+2 < len(indices); i += 3 {
+		binary.LittleEndian.PutUint32(face[1:], indices[i])
+		binary.LittleEndian.PutUint32(face[5:], indices[i+1])
+		binary.LittleEndian.PutUint32(face[9:], indices[i+2])
+		if _, err := w.Write(face); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The parts of the glTF 2.0 json document needed to describe a single mesh
+type gltfDocument struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       int              `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes"`
+	Buffers     []gltfBuffer     `json:"buffers"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Accessors   []gltfAccessor   `json:"accessors"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+	Name string `json:"name"`
+	Mesh int    `json:"mesh"`
+}
+
+type gltfMesh struct {
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Mode       int            `json:"mode"`
+}
+
+type gltfBuffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride,omitempty"`
+	Target     int `json:"target"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ByteOffset    int       `json:"byteOffset"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float32 `json:"min,omitempty"`
+	Max           []float32 `json:"max,omitempty"`
+}
+
+// Constants from the glTF 2.0 specification
+const (
+	gltfFloat        = 5126
+	gltfUnsignedInt  = 5125
+	gltfArrayBuffer  = 34962
+	gltfElementArray = 34963
+	gltfTriangles    = 4
+
+	glbMagic     = 0x46546C67 // "glTF"
+	glbChunkJSON = 0x4E4F534A // "JSON"
+	glbChunkBIN  = 0x004E4942 // "BIN\0"
+)
+
+// Writes the mesh as a binary glTF 2.0 file with interleaved positions, normals, biomes and ice.
+// Biomes and ice are the custom _BIOME and _ICE attributes, and the biome colors are added as vertex colors.
+func writeGLB(w io.Writer, vertices []float32, indices []uint32, scale float32) error {
+	numVertices := len(vertices) / vertexStride
+
+	// Accessors cannot be empty, and the bounds of the positions are found from the vertices
+	if numVertices == 0 || len(indices) == 0 {
+		return fmt.Errorf("a glTF mesh needs at least one triangle, got %d vertices and %d indices", numVertices, len(indices))
+	}
+	colored := hasBiomes(vertices)
+
+	// Build the binary chunk: interleaved vertices followed by the indices and the colors
+	vertexBytes := numVertices * vertexStride * 4
+	indexBytes := len(indices) * 4
+	colorBytes := 0
+	if colored {
+		colorBytes = numVertices * 3 * 4
+	}
+	bin := make([]byte, vertexBytes+indexBytes+colorBytes)
+
+	min := []float32{math.MaxFloat32, math.MaxFloat32, math.MaxFloat32}
+	max := []float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32}
+
+	for i := 0; i < numVertices; i++ {
+		for k := 0; k < vertexStride; k++ {
+			value := vertices[i*vertexStride+k]
+			if k < 3 {
+				value *= scale
+				// The position accessor is required to have bounds
+				min[k] = float32(math.Min(float64(min[k]), float64(value)))
+				max[k] = float32(math.Max(float64(max[k]), float64(value)))
+			}
+			binary.LittleEndian.PutUint32(bin[(i*vertexStride+k)*4:], math.Float32bits(value))
+		}
+	}
+	for i, index := range indices {
+		binary.LittleEndian.PutUint32(bin[vertexBytes+i*4:], index)
+	}
+	if colored {
+		for i := 0; i < numVertices; i++ {
+			col := biomeColor(vertices[i*vertexStride:])
+			for k := range col {
+				binary.LittleEndian.PutUint32(bin[vertexBytes+indexBytes+(i*3+k)*4:], math.Float32bits(col[k]))
+			}
+		}
+	}
+
+	doc := gltfDocument{
+		Asset:  gltfAsset{"2.0", "planet generator"},
+		Scene:  0,
+		Scenes: []gltfScene{{[]int{0}}},
+		Nodes:  []gltfNode{{"planet", 0}},
+		Meshes: []gltfMesh{{[]gltfPrimitive{{
+			map[string]int{"POSITION": 0, "NORMAL": 1, "_BIOME": 3, "_ICE": 4},
+			2,
+			gltfTriangles,
+		}}}},
+		Buffers: []gltfBuffer{{len(bin)}},
+		BufferViews: []gltfBufferView{
+			{0, 0, vertexBytes, vertexStride * 4, gltfArrayBuffer},
+			{0, vertexBytes, indexBytes, 0, gltfElementArray},
+		},
+		Accessors: []gltfAccessor{
+			{0, 0, gltfFloat, numVertices, "VEC3", min, max},
+			{0, 12, gltfFloat, numVertices, "VEC3", nil, nil},
+			{1, 0, gltfUnsignedInt, len(indices), "SCALAR", nil, nil},
+			{0, biomeOffset * 4, gltfFloat, numVertices, "SCALAR", nil, nil},
+			{0, iceOffset * 4, gltfFloat, numVertices, "SCALAR", nil, nil},
+		},
+	}
+	if colored {
+		doc.BufferViews = append(doc.BufferViews, gltfBufferView{0, vertexBytes + indexBytes, colorBytes, 0, gltfArrayBuffer})
+		doc.Accessors = append(doc.Accessors, gltfAccessor{2, 0, gltfFloat, numVertices, "VEC3", nil, nil})
+		doc.Meshes[0].Primitives[0].Attributes["COLOR_0"] = 5
+	}
+
+	jsonChunk, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	// Chunks must be 4-byte aligned, json is padded with spaces and binary data with zeros
+	for len(jsonChunk)%4 != 0 {
+		jsonChunk = append(jsonChunk, ' ')
+	}
+	for len(bin)%4 != 0 {
+		bin = append(bin, 0)
+	}
+
+	header := make([]byte, 12)
+	binary.LittleEndian.PutUint32(header[0:], glbMagic)
+	binary.LittleEndian.PutUint32(header[4:], 2)
+	binary.LittleEndian.PutUint32(header[8:], uint32(12+8+len(jsonChunk)+8+len(bin)))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if err := writeGLBChunk(w, glbChunkJSON, jsonChunk); err != nil {
+		return err
+	}
+	return writeGLBChunk(w, glbChunkBIN, bin)
+}
+
+func writeGLBChunk(w io.Writer, chunkType uint32, data []byte) error {
+	chunkHeader := make([]byte, 8)
+	binary.LittleEndian.PutUint32(chunkHeader[0:], uint32(len(data)))
+	binary.LittleEndian.PutUint32(chunkHeader[4:], chunkType)
+
+	if _, err := w.Write(chunkHeader); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
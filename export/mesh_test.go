@@ -0,0 +1,191 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+)
+
+// A small planet with or without biome colors
+func testMesh(t *testing.T, climate bool) ([]float32, []uint32) {
+	t.Helper()
+
+	shape := generation.DefaultEarth().Shape
+	shape.Res = 12
+	shape.HasClimate = climate
+
+	vertices, indices, err := generation.GenPlanet(shape)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasBiomes(vertices) != climate {
+		t.Fatalf("planet with climate %v has biomes %v", climate, hasBiomes(vertices))
+	}
+	return vertices, indices
+}
+
+func TestWriteOBJ(t *testing.T) {
+	for _, climate := range []bool{false, true} {
+		vertices, indices := testMesh(t, climate)
+
+		var buf bytes.Buffer
+		if err := writeOBJ(&buf, vertices, indices, 2); err != nil {
+			t.Fatal(err)
+		}
+
+		counts := map[string]int{}
+		wantFields := 4
+		if climate {
+			wantFields = 7
+		}
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) == 0 || fields[0] == "#" {
+				continue
+			}
+			counts[fields[0]]++
+			if fields[0] == "v" && len(fields) != wantFields {
+				t.Fatalf("climate %v: vertex line %q has %d fields, want %d", climate, scanner.Text(), len(fields), wantFields)
+			}
+		}
+
+		numVertices := len(vertices) / vertexStride
+		if counts["v"] != numVertices || counts["vn"] != numVertices || counts["f"] != len(indices)/3 {
+			t.Errorf("climate %v: %d positions, %d normals and %d faces, want %d, %d and %d",
+				climate, counts["v"], counts["vn"], counts["f"], numVertices, numVertices, len(indices)/3)
+		}
+	}
+}
+
+func TestWritePLY(t *testing.T) {
+	for _, climate := range []bool{false, true} {
+		vertices, indices := testMesh(t, climate)
+
+		var buf bytes.Buffer
+		if err := writePLY(&buf, vertices, indices, 2); err != nil {
+			t.Fatal(err)
+		}
+
+		data := buf.Bytes()
+		end := bytes.Index(data, []byte("end_header\n"))
+		if end < 0 || !bytes.HasPrefix(data, []byte("ply\nformat binary_little_endian 1.0\n")) {
+			t.Fatalf("climate %v: file does not start with a binary PLY header", climate)
+		}
+		header := string(data[:end])
+		body := data[end+len("end_header\n"):]
+
+		numVertices, numFaces := len(vertices)/vertexStride, len(indices)/3
+		for _, line := range []string{
+			fmt.Sprintf("element vertex %d\n", numVertices),
+			fmt.Sprintf("element face %d\n", numFaces),
+			"property float biome\nproperty float ice\n",
+		} {
+			if !strings.Contains(header, line) {
+				t.Errorf("climate %v: header has no line %q", climate, line)
+			}
+		}
+		if strings.Contains(header, "property uchar red") != climate {
+			t.Errorf("climate %v: header has colors %v", climate, !climate)
+		}
+
+		// Every vertex is its floats and maybe a color, every face a count and three indices
+		vertexSize := vertexStride * 4
+		if climate {
+			vertexSize += 3
+		}
+		if want := numVertices*vertexSize + numFaces*13; len(body) != want {
+			t.Errorf("climate %v: body is %d bytes, want %d", climate, len(body), want)
+		}
+	}
+}
+
+func TestWriteGLB(t *testing.T) {
+	for _, climate := range []bool{false, true} {
+		vertices, indices := testMesh(t, climate)
+
+		var buf bytes.Buffer
+		if err := writeGLB(&buf, vertices, indices, 2); err != nil {
+			t.Fatal(err)
+		}
+		data := buf.Bytes()
+
+		if binary.LittleEndian.Uint32(data[0:]) != glbMagic || binary.LittleEndian.Uint32(data[4:]) != 2 {
+			t.Fatalf("climate %v: header is not glTF 2.0", climate)
+		}
+		if length := binary.LittleEndian.Uint32(data[8:]); int(length) != len(data) {
+			t.Errorf("climate %v: header says %d bytes, file is %d", climate, length, len(data))
+		}
+
+		// The json chunk is followed by the binary chunk, both 4-byte aligned
+		jsonLength := int(binary.LittleEndian.Uint32(data[12:]))
+		if binary.LittleEndian.Uint32(data[16:]) != glbChunkJSON || jsonLength%4 != 0 {
+			t.Fatalf("climate %v: first chunk is not an aligned json chunk", climate)
+		}
+		binStart := 20 + jsonLength
+		binLength := int(binary.LittleEndian.Uint32(data[binStart:]))
+		if binary.LittleEndian.Uint32(data[binStart+4:]) != glbChunkBIN || binLength%4 != 0 {
+			t.Fatalf("climate %v: second chunk is not an aligned binary chunk", climate)
+		}
+		if binStart+8+binLength != len(data) {
+			t.Errorf("climate %v: chunks end at %d, file is %d bytes", climate, binStart+8+binLength, len(data))
+		}
+
+		var doc gltfDocument
+		if err := json.Unmarshal(data[20:binStart], &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc.Buffers[0].ByteLength > binLength || binLength-doc.Buffers[0].ByteLength >= 4 {
+			t.Errorf("climate %v: buffer is %d bytes in a %d byte chunk", climate, doc.Buffers[0].ByteLength, binLength)
+		}
+
+		attributes := doc.Meshes[0].Primitives[0].Attributes
+		if _, ok := attributes["COLOR_0"]; ok != climate {
+			t.Errorf("climate %v: mesh has vertex colors %v", climate, ok)
+		}
+		numVertices := len(vertices) / vertexStride
+		for name, accessor := range attributes {
+			if count := doc.Accessors[accessor].Count; count != numVertices {
+				t.Errorf("climate %v: %s has %d elements, want %d", climate, name, count, numVertices)
+			}
+		}
+		if count := doc.Accessors[doc.Meshes[0].Primitives[0].Indices].Count; count != len(indices) {
+			t.Errorf("climate %v: indices have %d elements, want %d", climate, count, len(indices))
+		}
+
+		// Every accessor fits in its buffer view
+		for i, accessor := range doc.Accessors {
+			view := doc.BufferViews[accessor.BufferView]
+			size := map[string]int{"SCALAR": 4, "VEC3": 12}[accessor.Type]
+			stride := view.ByteStride
+			if stride == 0 {
+				stride = size
+			}
+			if end := accessor.ByteOffset + (accessor.Count-1)*stride + size; end > view.ByteLength {
+				t.Errorf("climate %v: accessor %d ends at %d in a view of %d bytes", climate, i, end, view.ByteLength)
+			}
+		}
+	}
+}
+
+func TestWriteGLBEmpty(t *testing.T) {
+	// A lake mesh of a planet without lakes has no triangles, which glTF cannot hold
+	var buf bytes.Buffer
+	if err := writeGLB(&buf, nil, nil, 1); err == nil {
+		t.Errorf("writing an empty mesh did not fail")
+	}
+}
+
+func TestWriteMeshFormat(t *testing.T) {
+	vertices, indices := testMesh(t, false)
+	if err := WriteMesh(filepath.Join(t.TempDir(), "planet.stl"), vertices, indices, 1); err == nil {
+		t.Errorf("writing an .stl file did not fail")
+	}
+}
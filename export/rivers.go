@@ -0,0 +1,102 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+)
+
+/*
+WriteRivers writes the rivers of a planet as lines to a file. The format is picked from the file extension:
+".obj" for Wavefront OBJ polylines and ".json" for a list of rivers with the points and flow of every river.
+
+Parameters:
+- path: the file to write to
+- rivers: the rivers of the planet, as returned by generation.GenPlanetWithWater
+- scale: what every point is multiplied by, usually the planet radius
+
+Example usage:
+
+	_, _, water, err := generation.GenPlanetWithWater(settings.Shape)
+	err = export.WriteRivers("rivers.obj", water.Rivers, settings.Shape.Radius)
+*/
+func WriteRivers(path string, rivers []generation.River, scale float32) error {
+	var write func(io.Writer, []generation.River, float32) error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".obj":
+		write = writeRiversOBJ
+	case ".json":
+		write = writeRiversJSON
+	default:
+		return fmt.Errorf("unsupported river format %q, expected .obj or .json", filepath.Ext(path))
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	if err := write(w, rivers, scale); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %q: %v", path, err)
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Writes every river as a polyline of 1-indexed points
+func writeRiversOBJ(w io.Writer, rivers []generation.River, scale float32) error {
+	fmt.Fprintf(w, "# Planet generator rivers\n# %d rivers\n", len(rivers))
+
+	index := 1
+	for _, river := range rivers {
+		for _, p := range river.Points {
+			fmt.Fprintf(w, "v %g %g %g\n", p[0]*scale, p[1]*scale, p[2]*scale)
+		}
+
+		fmt.Fprint(w, "l")
+		for range river.Points {
+			fmt.Fprintf(w, " %d", index)
+			index++
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The layout of a river in JSON files
+type riverJSON struct {
+	Points [][3]float32 `json:"points"`
+	Flow   []float32    `json:"flow"`
+}
+
+// Writes the rivers as a JSON object with a list of rivers
+func writeRiversJSON(w io.Writer, rivers []generation.River, scale float32) error {
+	out := struct {
+		Rivers []riverJSON `json:"rivers"`
+	}{make([]riverJSON, len(rivers))}
+
+	for i, river := range rivers {
+		out.Rivers[i] = riverJSON{make([][3]float32, len(river.Points)), river.Flow}
+		for j, p := range river.Points {
+			out.Rivers[i].Points[j] = [3]float32{p[0] * scale, p[1] * scale, p[2] * scale}
+		}
+	}
+
+	return json.NewEncoder(w).Encode(out)
+}
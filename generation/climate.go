@@ -0,0 +1,301 @@
+package generation
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Biome names the kind of landscape at a vertex, from its temperature and precipitation
+type Biome uint8
+
+const (
+	NoBiome                  Biome = iota // the planet has no climate
+	OceanBiome                            // under the sea
+	IceBiome                              // frozen all year
+	TundraBiome                           // too cold for trees
+	TaigaBiome                            // cold forests of conifers
+	ColdDesertBiome                       // cold and dry steppes and deserts
+	TemperateForestBiome                  // forests that change with the seasons
+	TemperateRainforestBiome              // mild and very wet forests
+	DesertBiome                           // hot and dry
+	SavannaBiome                          // hot grassland with scattered trees
+	TropicalForestBiome                   // hot forests with a dry season
+	RainforestBiome                       // hot and wet all year
+)
+
+// Biomes lists every biome in the order of their values
+var Biomes = []Biome{
+	NoBiome, OceanBiome, IceBiome, TundraBiome, TaigaBiome, ColdDesertBiome, TemperateForestBiome,
+	TemperateRainforestBiome, DesertBiome, SavannaBiome, TropicalForestBiome, RainforestBiome,
+}
+
+// The names and colors of the biomes, in the order of their values
+var biomeNames = []string{
+	"none", "ocean", "ice", "tundra", "taiga", "cold desert", "temperate forest",
+	"temperate rainforest", "desert", "savanna", "tropical forest", "rainforest",
+}
+var biomeColors = []mgl32.Vec3{
+	{0.00, 0.00, 0.00},
+	{0.10, 0.22, 0.45},
+	{0.92, 0.94, 0.98},
+	{0.60, 0.62, 0.50},
+	{0.20, 0.35, 0.22},
+	{0.70, 0.65, 0.48},
+	{0.33, 0.50, 0.12},
+	{0.13, 0.40, 0.20},
+	{0.93, 0.80, 0.52},
+	{0.70, 0.66, 0.28},
+	{0.35, 0.52, 0.08},
+	{0.08, 0.38, 0.06},
+}
+
+func (b Biome) String() string {
+	if int(b) < len(biomeNames) {
+		return biomeNames[b]
+	}
+	return "unknown"
+}
+
+// Color returns the color the viewer and the exporters give a biome
+func (b Biome) Color() mgl32.Vec3 {
+	if int(b) < len(biomeColors) {
+		return biomeColors[b]
+	}
+	return mgl32.Vec3{}
+}
+
+// Climate holds the climate of every vertex of a planet mesh
+type Climate struct {
+	Temperature   []float32 // yearly mean in °C
+	Precipitation []float32 // cm a year
+	Biomes        []Biome
+}
+
+// Winds with less moisture than this have rained out
+const minMoisture = 1e-3
+
+// How many times the moisture is averaged with the neighbours of every vertex
+const moistureBlurPasses = 4
+
+/*
+GenClimate finds the temperature and precipitation of every vertex of a planet mesh and sorts the vertices into biomes,
+as described by the climate settings of a planet shape. Temperatures fall with latitude and altitude, and winds
+carry moisture from the sea inland until it rains out or is stopped by mountains.
+
+Parameters:
+- points: the points of the planet, where 1.0 is sea level
+- indices: the indices of the triangles of the planet
+- shape: the planet shape struct containing the climate settings
+
+Returns:
+- climate: the climate of every vertex, empty if the shape has no climate
+
+Example usage:
+
+	settings := DefaultEarth()
+	settings.Shape.HasClimate = true
+
+	points, indices := genOctahedron(100)
+	normalizePointDistances(points)
+	err := GenTerrain(points, settings.Shape)
+
+	climate := GenClimate(points, indices, settings.Shape)
+	fmt.Println(climate.Biomes[0])
+*/
+func GenClimate(points []mgl32.Vec3, indices []uint32, shape PlanetShape) Climate {
+	if !shape.HasClimate {
+		return Climate{}
+	}
+
+	graph := newVertexGraph(len(points), indices)
+
+	directions := make([]mgl32.Vec3, len(points))
+	heights := make([]float32, len(points))
+	for i, point := range points {
+		heights[i] = point.Len()
+		directions[i] = point.Mul(1.0 / heights[i])
+	}
+
+	climate := Climate{make([]float32, len(points)), make([]float32, len(points)), make([]Biome, len(points))}
+	moisture := windMoisture(&graph, directions, heights, &shape)
+
+	for v := range points {
+		latitude := math.Asin(float64(clamp(directions[v].Y(), -1, 1)))
+
+		temperature := seaLevelTemperature(latitude, &shape)
+		if heights[v] > 1.0 {
+			temperature -= shape.LapseRate * (heights[v] - 1.0)
+		}
+		climate.Temperature[v] = temperature
+		climate.Precipitation[v] = shape.Rainfall * moisture[v] * rainBelt(latitude)
+
+		if heights[v] < 1.0 {
+			climate.Biomes[v] = OceanBiome
+		} else {
+			climate.Biomes[v] = ClassifyBiome(climate.Temperature[v], climate.Precipitation[v])
+		}
+	}
+
+	return climate
+}
+
+/*
+ClassifyBiome picks the biome of a place on land from its climate, like a Whittaker diagram
+
+Parameters:
+- temperature: the yearly mean temperature in °C
+- precipitation: the yearly precipitation in cm
+
+Returns:
+- biome: the biome that grows in the climate
+*/
+func ClassifyBiome(temperature, precipitation float32) Biome {
+	switch {
+	case temperature < -10:
+		return IceBiome
+	case temperature < -2:
+		return TundraBiome
+	case temperature < 5:
+		if precipitation < 25 {
+			return ColdDesertBiome
+		}
+		return TaigaBiome
+	case temperature < 20:
+		switch {
+		case precipitation < 25:
+			return DesertBiome
+		case precipitation < 50:
+			return ColdDesertBiome
+		case precipitation < 200:
+			return TemperateForestBiome
+		}
+		return TemperateRainforestBiome
+	}
+
+	switch {
+	case precipitation < 50:
+		return DesertBiome
+	case precipitation < 150:
+		return SavannaBiome
+	case precipitation < 250:
+		return TropicalForestBiome
+	}
+	return RainforestBiome
+}
+
+// The yearly mean temperature at sea level at a latitude in radians.
+// Tilted planets spread the sunlight more evenly, which warms the poles and cools the equator.
+func seaLevelTemperature(latitude float64, shape *PlanetShape) float32 {
+	// The yearly mean sunlight at a latitude is about 1 + s2 * P2(sin(latitude)), where s2 depends on the tilt
+	p2 := func(x float64) float64 { return (3.0*x*x - 1.0) * 0.5 }
+	sunlight := func(latitude, tilt float64) float64 {
+		return 1.0 - 0.625*p2(math.Cos(tilt))*p2(math.Sin(latitude))
+	}
+
+	// The temperatures of the settings are those of an upright planet
+	tilt := float64(shape.AxialTilt) * math.Pi / 180.0
+	equator, pole := sunlight(0, 0), sunlight(math.Pi*0.5, 0)
+	t := (sunlight(latitude, tilt) - pole) / (equator - pole)
+
+	return shape.PoleTemperature + (shape.EquatorTemperature-shape.PoleTemperature)*float32(t)
+}
+
+// How much of the moisture in the air rains out at a latitude in radians. Rising air makes the
+// equator and 60° wet, and sinking air makes 30° and the poles dry.
+func rainBelt(latitude float64) float32 {
+	return float32(0.6 + 0.4*math.Cos(6.0*latitude))
+}
+
+// The direction of the prevailing wind at a point on the unit sphere: easterly trade winds towards the equator,
+// westerlies towards the poles between 30° and 60°, and easterlies from the poles
+func prevailingWind(direction mgl32.Vec3) mgl32.Vec3 {
+	east := mgl32.Vec3{0, 1, 0}.Cross(direction)
+	if east.Len() < 1e-6 {
+		// The wind has no direction right at the poles
+		return mgl32.Vec3{}
+	}
+	east = east.Normalize()
+	north := direction.Cross(east)
+
+	zonal, meridional := float32(-1.0), float32(-0.5)
+	if sinLatitude := math.Abs(float64(direction.Y())); sinLatitude > 0.5 && sinLatitude < math.Sqrt(3)*0.5 {
+		zonal, meridional = 1.0, 0.5
+	}
+	if direction.Y() < 0 {
+		meridional = -meridional
+	}
+	return east.Mul(zonal).Add(north.Mul(meridional)).Normalize()
+}
+
+// Carries moisture from the sea along the prevailing winds. The air dries out with the distance it travels over land,
+// and air that has climbed over mountains can only carry what it can hold at their height, which leaves the land behind
+// them in a rain shadow. Returns the moisture that the wind brings to every vertex, 1 over the sea.
+func windMoisture(graph *vertexGraph, directions []mgl32.Vec3, heights []float32, shape *PlanetShape) []float32 {
+	// The neighbour that the wind at every vertex comes from, -1 where there is no wind
+	upwind := make([]int32, len(directions))
+	for v := range directions {
+		upwind[v] = -1
+		from := prevailingWind(directions[v]).Mul(-1)
+		best := float32(0.0)
+		for _, n := range graph.next(uint32(v)) {
+			if along := directions[n].Sub(directions[v]).Normalize().Dot(from); along > best {
+				upwind[v], best = int32(n), along
+			}
+		}
+	}
+
+	const (
+		unknown = iota
+		following
+		known
+	)
+	state := make([]uint8, len(directions))
+	incoming := make([]float32, len(directions)) // what the wind brings to a vertex, which rains out on slopes facing the wind
+	carried := make([]float32, len(directions))  // what the wind takes on to the next vertex
+	path := []int32{}
+
+	for start := range directions {
+		// Follow the wind back until it comes from the sea or from a vertex that is already known
+		path = path[:0]
+		for v := int32(start); v >= 0 && state[v] == unknown; v = upwind[v] {
+			if heights[v] < 1.0 {
+				incoming[v], carried[v], state[v] = 1.0, 1.0, known
+				break
+			}
+			state[v] = following
+			path = append(path, v)
+		}
+
+		// Then blow the moisture back along the path. Winds that go around in circles over land bring no moisture.
+		for i := len(path) - 1; i >= 0; i-- {
+			v := path[i]
+			if u := upwind[v]; u >= 0 && state[u] == known {
+				distance := directions[v].Sub(directions[u]).Len()
+				incoming[v] = carried[u] * float32(math.Exp(float64(-distance/shape.MoistureReach)))
+				if incoming[v] < minMoisture {
+					incoming[v] = 0
+				}
+			}
+			holds := float32(math.Exp(float64(-shape.RainShadow * (heights[v] - 1.0))))
+			carried[v] = float32(math.Min(float64(incoming[v]), float64(holds)))
+			state[v] = known
+		}
+	}
+
+	// Following single vertices leaves streaks along the wind, blur them away
+	for pass := 0; pass < moistureBlurPasses; pass++ {
+		blurred := make([]float32, len(incoming))
+		for v := range incoming {
+			sum := incoming[v]
+			neighbours := graph.next(uint32(v))
+			for _, n := range neighbours {
+				sum += incoming[n]
+			}
+			blurred[v] = sum / float32(len(neighbours)+1)
+		}
+		incoming = blurred
+	}
+
+	return incoming
+}
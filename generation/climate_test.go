@@ -0,0 +1,141 @@
+package generation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestClassifyBiome(t *testing.T) {
+	tests := []struct {
+		temperature, precipitation float32
+		want                       Biome
+	}{
+		{-20, 100, IceBiome},
+		{-5, 100, TundraBiome},
+		{0, 10, ColdDesertBiome},
+		{0, 100, TaigaBiome},
+		{10, 10, DesertBiome},
+		{10, 40, ColdDesertBiome},
+		{10, 100, TemperateForestBiome},
+		{10, 300, TemperateRainforestBiome},
+		{25, 20, DesertBiome},
+		{25, 100, SavannaBiome},
+		{25, 200, TropicalForestBiome},
+		{25, 400, RainforestBiome},
+	}
+
+	for _, test := range tests {
+		if got := ClassifyBiome(test.temperature, test.precipitation); got != test.want {
+			t.Errorf("ClassifyBiome(%v, %v) = %v, want %v", test.temperature, test.precipitation, got, test.want)
+		}
+	}
+}
+
+func TestSeaLevelTemperature(t *testing.T) {
+	shape := DefaultEarth().Shape
+	shape.AxialTilt = 0
+
+	equator, pole := seaLevelTemperature(0, &shape), seaLevelTemperature(math.Pi*0.5, &shape)
+	if math.Abs(float64(equator-shape.EquatorTemperature)) > 1e-3 || math.Abs(float64(pole-shape.PoleTemperature)) > 1e-3 {
+		t.Errorf("upright planet is %v at the equator and %v at the poles, want %v and %v",
+			equator, pole, shape.EquatorTemperature, shape.PoleTemperature)
+	}
+	if middle := seaLevelTemperature(math.Pi*0.25, &shape); middle >= equator || middle <= pole {
+		t.Errorf("45° is %v, want between %v and %v", middle, pole, equator)
+	}
+
+	// Tilting the planet spreads the sunlight towards the poles
+	shape.AxialTilt = 45
+	if tilted := seaLevelTemperature(math.Pi*0.5, &shape); tilted <= pole {
+		t.Errorf("poles of a tilted planet are %v, want warmer than %v", tilted, pole)
+	}
+	if tilted := seaLevelTemperature(0, &shape); tilted >= equator {
+		t.Errorf("equator of a tilted planet is %v, want colder than %v", tilted, equator)
+	}
+}
+
+// A ring of vertices around the equator where the wind blows from every vertex to the next,
+// with sea under the first vertices and land under the rest
+func equatorRing(mountain float32) (*vertexGraph, []mgl32.Vec3, []float32) {
+	const count = 200
+	graph := vertexGraph{make([]int32, count+1), make([]uint32, 0, count*2)}
+	directions := make([]mgl32.Vec3, count)
+	heights := make([]float32, count)
+
+	for i := range directions {
+		angle := 2.0 * math.Pi * float64(i) / count
+		directions[i] = mgl32.Vec3{float32(math.Cos(angle)), 0, float32(math.Sin(angle))}
+
+		heights[i] = 1.01
+		if i < 20 {
+			heights[i] = 0.99
+		}
+		if i >= 30 && i < 35 {
+			heights[i] += mountain
+		}
+
+		graph.neighbours = append(graph.neighbours, uint32((i+count-1)%count), uint32((i+1)%count))
+		graph.offsets[i+1] = int32(len(graph.neighbours))
+	}
+	return &graph, directions, heights
+}
+
+func TestRainShadow(t *testing.T) {
+	shape := DefaultEarth().Shape
+
+	graph, directions, heights := equatorRing(0)
+	flat := windMoisture(graph, directions, heights, &shape)
+	graph, directions, heights = equatorRing(0.1)
+	shadowed := windMoisture(graph, directions, heights, &shape)
+
+	if flat[10] != 1 {
+		t.Errorf("moisture over the sea is %v, want 1", flat[10])
+	}
+	if flat[25] >= flat[10] || flat[50] >= flat[25] {
+		t.Errorf("moisture does not fall inland: %v over the sea, %v and %v further in", flat[10], flat[25], flat[50])
+	}
+	if shadowed[45] >= flat[45]*0.5 {
+		t.Errorf("moisture behind a mountain is %v, want well below the %v without it", shadowed[45], flat[45])
+	}
+	if math.Abs(float64(shadowed[24]-flat[24])) > 1e-3 {
+		t.Errorf("moisture in front of a mountain is %v, want the %v without it", shadowed[24], flat[24])
+	}
+}
+
+func TestGenPlanetBiomes(t *testing.T) {
+	shape := DefaultEarth().Shape
+	shape.Res = 40
+
+	vertices, _ := genPlanet(t, shape)
+	for i := 6; i < len(vertices); i += VertexStride {
+		if Biome(vertices[i]) != NoBiome {
+			t.Fatalf("vertex %d has biome %v without a climate", i/VertexStride, Biome(vertices[i]))
+		}
+	}
+
+	shape.HasClimate = true
+	vertices, _ = genPlanet(t, shape)
+	found := map[Biome]int{}
+	for i := 6; i < len(vertices); i += VertexStride {
+		biome := Biome(vertices[i])
+		if float32(biome) != vertices[i] || int(biome) >= len(Biomes) || biome == NoBiome {
+			t.Fatalf("vertex %d has biome %v", i/VertexStride, vertices[i])
+		}
+		found[biome]++
+	}
+	if found[OceanBiome] == 0 || found[IceBiome] == 0 || len(found) < 5 {
+		t.Errorf("earth has the biomes %v, want oceans, ice and more", found)
+	}
+}
+
+func TestClimateOff(t *testing.T) {
+	shape := DefaultEarth().Shape
+	points, indices := genOctahedron(20)
+	normalizePointDistances(points)
+
+	if climate := GenClimate(points, indices, shape); climate.Biomes != nil || climate.Temperature != nil {
+		t.Errorf("planet without a climate got %d biomes", len(climate.Biomes))
+	}
+}
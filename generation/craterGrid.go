@@ -0,0 +1,79 @@
+package generation
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The most cells a crater grid has along each axis
+const maxCraterGridSize = 64
+
+// A grid of cubes around the unit sphere where every cell lists the craters that reach into it, from the oldest to the newest.
+// Points on the sphere only have to look at the craters in their own cell.
+type craterGrid struct {
+	size  int
+	cells [][]int32
+}
+
+// Makes an empty grid with size cells along each axis
+func newCraterGrid(size int) *craterGrid {
+	size = int(math.Max(1, math.Min(float64(size), maxCraterGridSize)))
+	return &craterGrid{size, make([][]int32, size*size*size)}
+}
+
+// Picks a grid size where the cells are about as wide as the area a typical crater reaches
+func craterGridSize(craters []Crater, reach float32) int {
+	if len(craters) == 0 {
+		return 1
+	}
+
+	sum := float32(0.0)
+	for _, crater := range craters {
+		sum += crater.Radius * reach
+	}
+	width := 2.0 * sum / float32(len(craters))
+	if width <= 0 {
+		return maxCraterGridSize
+	}
+	return int(math.Ceil(float64(2.0 / width)))
+}
+
+// The cell along one axis that a coordinate between -1 and 1 is in, coordinates outside are moved to the closest cell
+func (g *craterGrid) axis(x float32) int {
+	i := int((x + 1.0) * 0.5 * float32(g.size))
+	if i < 0 {
+		return 0
+	}
+	if i >= g.size {
+		return g.size - 1
+	}
+	return i
+}
+
+// Adds a crater to every cell that overlaps the box from min to max.
+// Craters have to be added in order for the cells to stay sorted.
+func (g *craterGrid) add(index int, min, max mgl32.Vec3) {
+	g.each(min, max, func(cell int) {
+		g.cells[cell] = append(g.cells[cell], int32(index))
+	})
+}
+
+// Calls f with every cell that overlaps the box from min to max
+func (g *craterGrid) each(min, max mgl32.Vec3, f func(cell int)) {
+	x0, y0, z0 := g.axis(min.X()), g.axis(min.Y()), g.axis(min.Z())
+	x1, y1, z1 := g.axis(max.X()), g.axis(max.Y()), g.axis(max.Z())
+
+	for x := x0; x <= x1; x++ {
+		for y := y0; y <= y1; y++ {
+			for z := z0; z <= z1; z++ {
+				f((x*g.size+y)*g.size + z)
+			}
+		}
+	}
+}
+
+// The craters in the cell a point is in
+func (g *craterGrid) at(point mgl32.Vec3) []int32 {
+	return g.cells[(g.axis(point.X())*g.size+g.axis(point.Y()))*g.size+g.axis(point.Z())]
+}
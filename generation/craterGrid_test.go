@@ -0,0 +1,111 @@
+package generation
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Craters like the ones on the moon, but many more and smaller
+func manyCraters(count uint32) CraterSettings {
+	settings := CraterSettingsOf(DefaultMoon().Shape)
+	settings.Count = count
+	settings.MinRadius = 0.005
+	settings.MaxRadius = 0.15
+	return settings
+}
+
+func TestCraterGridMatchesEveryCrater(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	settings := manyCraters(2000)
+	craters := genCraters(&settings, rng)
+
+	grid := newCraterField(&settings, Simplex{}, craters)
+	every := newCraterFieldOfSize(&settings, Simplex{}, craters, 1)
+	if grid.grid.size == 1 {
+		t.Fatal("the grid has a single cell, want a grid that splits up the craters")
+	}
+
+	for i := 0; i < 5000; i++ {
+		point := randomPointOnSphere(rng)
+		if a, b := grid.height(point), every.height(point); a != b {
+			t.Fatalf("height at %v is %v with a grid and %v without", point, a, b)
+		}
+	}
+
+	// The centers of the craters are where the levels of the newer craters are taken
+	for i, crater := range craters {
+		if grid.levels[i] != every.levels[i] {
+			t.Fatalf("level of crater %d at %v is %v with a grid and %v without", i, crater.Position, grid.levels[i], every.levels[i])
+		}
+	}
+}
+
+func TestCraterGridCells(t *testing.T) {
+	grid := newCraterGrid(4)
+	grid.add(0, mgl32.Vec3{-1, -1, -1}, mgl32.Vec3{-0.6, -0.6, -0.6})
+	grid.add(1, mgl32.Vec3{-0.6, -0.6, -0.6}, mgl32.Vec3{2, 2, 2})
+
+	if cell := grid.at(mgl32.Vec3{-0.9, -0.9, -0.9}); len(cell) != 2 || cell[0] != 0 || cell[1] != 1 {
+		t.Errorf("corner cell is %v, want both craters in order", cell)
+	}
+	// Points outside of the grid are in the closest cell
+	if cell := grid.at(mgl32.Vec3{3, 3, 3}); len(cell) != 1 || cell[0] != 1 {
+		t.Errorf("cell outside of the grid is %v, want the second crater", cell)
+	}
+
+	if size := newCraterGrid(1000).size; size != maxCraterGridSize {
+		t.Errorf("grid size is %d, want it limited to %d", size, maxCraterGridSize)
+	}
+}
+
+func BenchmarkCraterField(b *testing.B) {
+	directions := make([]mgl32.Vec3, 1000)
+	rng := rand.New(rand.NewSource(1))
+	for i := range directions {
+		directions[i] = randomPointOnSphere(rng)
+	}
+
+	for _, count := range []uint32{100, 1000, 10000} {
+		settings := manyCraters(count)
+		craters := genCraters(&settings, rand.New(rand.NewSource(1)))
+
+		for _, grid := range []bool{true, false} {
+			name := fmt.Sprintf("craters=%d/grid", count)
+			if !grid {
+				if count > 1000 {
+					// Far too slow without a grid
+					continue
+				}
+				name = fmt.Sprintf("craters=%d/every", count)
+			}
+
+			b.Run(name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					field := newCraterField(&settings, Simplex{}, craters)
+					if !grid {
+						field = newCraterFieldOfSize(&settings, Simplex{}, craters, 1)
+					}
+					for _, direction := range directions {
+						field.height(direction)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkGenCraters(b *testing.B) {
+	for _, count := range []uint32{100, 1000, 10000} {
+		settings := manyCraters(count)
+		settings.Spacing = 1
+
+		b.Run(fmt.Sprintf("craters=%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				genCraters(&settings, rand.New(rand.NewSource(1)))
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package generation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type Crater struct {
+	Position mgl32.Vec3
+	Radius   float32
+}
+
+// CraterSettings describes how many craters a crater field has, how large they are and what they look like.
+// Heights and widths are relative to the radius of each crater.
+type CraterSettings struct {
+	Count uint32
+
+	// Radii are drawn from a power law between the min and max radius, larger exponents give more small craters
+	MinRadius    float32
+	MaxRadius    float32
+	SizeExponent float32
+
+	RimWidth     float32
+	RimSteepness float32
+	RimNoise     float32 // how much the distance to the rim varies around the crater, between 0 and 0.5
+	Smoothness   float32
+	FloorHeight  float32
+
+	PeakHeight    float32 // height of the peak in the middle of large craters
+	PeakMinRadius float32 // the smallest crater with a central peak
+
+	EjectaHeight float32 // height of the rays of ejected rock around the rim
+	EjectaWidth  float32 // how far the ejecta reaches past the rim
+
+	// Craters are kept at least this many times the sum of their radii apart, 0 places them anywhere
+	Spacing float32
+}
+
+// How much of the crater the central peak covers
+const craterPeakWidth = 0.35
+
+/*
+CraterSettingsOf returns the crater settings of a planet shape
+
+Parameters:
+- shape: the planet shape to take the crater settings of
+
+Returns:
+- settings: the crater settings used by the default terrain of the shape
+
+Example usage:
+
+	craters := CraterSettingsOf(DefaultMoon().Shape)
+	craters.Count *= 2
+*/
+func CraterSettingsOf(shape PlanetShape) CraterSettings {
+	return CraterSettings{
+		shape.NumCraters,
+		shape.CraterMinRadius,
+		shape.CraterMaxRadius,
+		shape.CraterSizeExponent,
+		shape.CraterRimWidth,
+		shape.CraterRimSteepness,
+		shape.CraterRimNoise,
+		shape.CraterSmoothness,
+		shape.CraterFloorHeight,
+		shape.CraterPeakHeight,
+		shape.CraterPeakMinRadius,
+		shape.CraterEjectaHeight,
+		shape.CraterEjectaWidth,
+		shape.CraterSpacing,
+	}
+}
+
+// Checks that craters can be generated with the settings
+func (s *CraterSettings) validate() error {
+	switch {
+	case s.MinRadius < 0:
+		return fmt.Errorf("min radius must not be negative, got %g", s.MinRadius)
+	case s.MaxRadius < s.MinRadius:
+		return fmt.Errorf("max radius must not be less than the min radius %g, got %g", s.MinRadius, s.MaxRadius)
+	case s.SizeExponent >= 1 && s.MinRadius == 0 && s.Count > 0:
+		// The power law has no end towards zero for these exponents
+		return fmt.Errorf("min radius must be greater than 0 when the size exponent is 1 or more")
+	case s.RimWidth < 0:
+		return fmt.Errorf("rim width must not be negative, got %g", s.RimWidth)
+	case s.RimNoise < 0 || s.RimNoise >= 0.5:
+		return fmt.Errorf("rim noise must be at least 0 and less than 0.5, got %g", s.RimNoise)
+	case s.Smoothness < 0:
+		return fmt.Errorf("smoothness must not be negative, got %g", s.Smoothness)
+	case s.Count > 0 && s.Smoothness == 0:
+		// A crater smoothness of zero divides by zero in smoothMin
+		return fmt.Errorf("smoothness must be greater than 0 when there are craters")
+	case s.EjectaWidth < 0:
+		return fmt.Errorf("ejecta width must not be negative, got %g", s.EjectaWidth)
+	case s.Spacing < 0:
+		return fmt.Errorf("spacing must not be negative, got %g", s.Spacing)
+	}
+	return nil
+}
+
+// How far from its center a crater changes the terrain, relative to its radius
+func (s *CraterSettings) reach() float32 {
+	// Past the rim the crater shape is exactly zero once the cavity is deeper than the smoothing
+	reach := math.Max(float64(1.0+s.RimWidth), math.Sqrt(float64(1.0+s.Smoothness)))
+	if s.EjectaHeight != 0 {
+		reach = math.Max(reach, float64(1.0+s.EjectaWidth))
+	}
+	// Noisy rims can move every part of the crater outwards
+	return float32(reach / float64(1.0-s.RimNoise))
+}
+
+// A field of craters that is ready to calculate heights
+type craterField struct {
+	settings *CraterSettings
+	noise    Noise // adds detail to the rims and ejecta
+	craters  []Crater
+	levels   []float32 // the height of the older craters at the center of every crater
+	reach    float32
+	grid     *craterGrid
+}
+
+// Prepares craters, from the oldest to the newest crater, for calculating heights
+func newCraterField(settings *CraterSettings, noise Noise, craters []Crater) *craterField {
+	return newCraterFieldOfSize(settings, noise, craters, craterGridSize(craters, settings.reach()))
+}
+
+// Prepares craters for calculating heights with a grid of a given size, a size of 1 looks at every crater for every point
+func newCraterFieldOfSize(settings *CraterSettings, noise Noise, craters []Crater, size int) *craterField {
+	reach := settings.reach()
+	f := &craterField{settings, noise, craters, make([]float32, len(craters)), reach, newCraterGrid(size)}
+
+	for i, crater := range craters {
+		extent := crater.Radius * reach
+		box := mgl32.Vec3{extent, extent, extent}
+		f.grid.add(i, crater.Position.Sub(box), crater.Position.Add(box))
+	}
+
+	for i := range craters {
+		f.levels[i] = f.heightBefore(craters[i].Position, i)
+	}
+
+	return f
+}
+
+// Calculates the height of the terrain at a point from every crater
+func (f *craterField) height(point mgl32.Vec3) float32 {
+	return f.heightBefore(point, len(f.craters))
+}
+
+// Calculates the height of the terrain at a point from the first n craters
+func (f *craterField) heightBefore(point mgl32.Vec3, n int) float32 {
+	settings := f.settings
+	craterHeight := float32(0.0)
+
+	// Only the craters that reach into the cell of the point can change its height
+	for _, i := range f.grid.at(point) {
+		if int(i) >= n {
+			break
+		}
+		crater := &f.craters[i]
+
+		distance := point.Sub(crater.Position).Len()
+		if distance >= crater.Radius*f.reach {
+			continue
+		}
+		x := distance / crater.Radius
+
+		// Make the rim wobble, with detail that scales with the crater
+		if settings.RimNoise != 0 {
+			p := point.Mul(2.0 / crater.Radius)
+			x *= 1.0 + settings.RimNoise*f.noise.Sample(p.X(), p.Y(), p.Z())
+		}
+
+		cavity := x*x - 1.0
+		rimX := float32(math.Min(float64(x-1.0-settings.RimWidth), 0))
+		rim := settings.RimSteepness * rimX * rimX
+
+		craterShape := smoothMax(cavity, settings.FloorHeight, settings.Smoothness)
+		craterShape = smoothMin(craterShape, float32(rim), settings.Smoothness)
+
+		// Large craters rebound into a peak in the middle
+		if settings.PeakHeight != 0 && crater.Radius >= settings.PeakMinRadius && x < craterPeakWidth {
+			t := 1.0 - (x*x)/(craterPeakWidth*craterPeakWidth)
+			craterShape += settings.PeakHeight * t * t
+		}
+
+		// Rays of ejected rock that thin out away from the rim
+		if settings.EjectaHeight != 0 && settings.EjectaWidth > 0 && x > 0.9 {
+			craterShape += settings.EjectaHeight * craterEjecta(point, crater, f.noise, x, settings.EjectaWidth)
+		}
+
+		// Newer craters blast away the older craters inside of them, down to the level of their center
+		erased := 1.0 - smoothstep(0.8, 1.0, x)
+		craterHeight += (f.levels[i]-craterHeight)*erased + craterShape*crater.Radius
+	}
+
+	return craterHeight
+}
+
+// The ejecta around a crater at a point x crater radii from its center, between 0 and 1
+func craterEjecta(point mgl32.Vec3, crater *Crater, noise Noise, x, width float32) float32 {
+	// Fade in over the rim and out towards the edge of the ejecta
+	t := 1.0 - clamp((x-1.0)/width, 0, 1)
+	blanket := smoothstep(0.9, 1.1, x) * t * t
+
+	// The rays only depend on the direction from the crater, so sample noise on a circle around it
+	direction := point.Sub(crater.Position.Mul(point.Dot(crater.Position))).Normalize()
+	p := direction.Mul(6.0).Add(crater.Position.Mul(11.0))
+	rays := 0.5 + 0.5*noise.Sample(p.X(), p.Y(), p.Z())
+
+	return blanket * rays * rays
+}
+
+/*
+genCraters randomly places craters on the unit sphere, from the oldest to the newest crater
+
+Parameters:
+- settings: the number of craters, the distribution of their radii and how far apart they are kept
+- rng: the random number generator to draw the craters from
+
+Returns:
+- craters: the position and radius of every crater
+*/
+func genCraters(settings *CraterSettings, rng *rand.Rand) []Crater {
+	craters := make([]Crater, settings.Count)
+
+	// The centers of the placed craters, with cells about as wide as the largest spacing between two craters
+	centers := newCraterGrid(maxCraterGridSize)
+	if width := 2.0 * settings.Spacing * settings.MaxRadius; width > 0 {
+		centers = newCraterGrid(int(math.Ceil(float64(2.0 / width))))
+	}
+
+	for i := 0; i < len(craters); i++ {
+		radius := float32(powerLaw(rng.Float64(), float64(settings.MinRadius), float64(settings.MaxRadius), float64(settings.SizeExponent)))
+
+		if settings.Spacing == 0 {
+			craters[i] = Crater{randomPointOnSphere(rng), radius}
+			continue
+		}
+
+		// Throw darts until one lands far enough from every older crater, or keep the one with the most room
+		best, bestRoom := mgl32.Vec3{}, float32(math.Inf(-1))
+		for attempt := 0; attempt < craterPlacementAttempts; attempt++ {
+			position := randomPointOnSphere(rng)
+			room := craterRoom(craters, centers, position, radius, settings)
+			if room > bestRoom {
+				best, bestRoom = position, room
+			}
+			if room >= 1 {
+				break
+			}
+		}
+		craters[i] = Crater{best, radius}
+		centers.add(i, best, best)
+	}
+	return craters
+}
+
+// How many positions a crater tries before settling for the one with the most room
+const craterPlacementAttempts = 30
+
+// The distance from a crater to the closest of the placed craters, relative to the distance they should be kept apart.
+// 1 or more means the crater is far enough from every other crater.
+func craterRoom(craters []Crater, centers *craterGrid, position mgl32.Vec3, radius float32, settings *CraterSettings) float32 {
+	room := float32(math.Inf(1))
+
+	// Craters further away than this are always far enough
+	distance := settings.Spacing * (radius + settings.MaxRadius)
+	box := mgl32.Vec3{distance, distance, distance}
+
+	centers.each(position.Sub(box), position.Add(box), func(cell int) {
+		for _, j := range centers.cells[cell] {
+			crater := &craters[j]
+			r := position.Sub(crater.Position).Len() / (settings.Spacing * (radius + crater.Radius))
+			if r < room {
+				room = r
+			}
+		}
+	})
+	return room
+}
+
+// Maps u between 0 and 1 to a value between min and max, where values are drawn with a density of value^-exponent
+func powerLaw(u, min, max, exponent float64) float64 {
+	if min == max {
+		return min
+	}
+	if exponent == 1 {
+		return min * math.Pow(max/min, u)
+	}
+
+	e := 1.0 - exponent
+	low, high := math.Pow(min, e), math.Pow(max, e)
+	return math.Pow(low+u*(high-low), 1.0/e)
+}
+
+// Draws a point from an even distribution over the unit sphere
+func randomPointOnSphere(rng *rand.Rand) mgl32.Vec3 {
+	// Slices of the sphere of equal height have equal area, so the height is drawn evenly
+	y := rng.Float64()*2.0 - 1.0
+	theta := rng.Float64() * 2.0 * math.Pi
+	r := math.Sqrt(1.0 - y*y)
+
+	return mgl32.Vec3{float32(r * math.Cos(theta)), float32(y), float32(r * math.Sin(theta))}
+}
+
+func clamp(x, min, max float32) float32 {
+	return float32(math.Min(math.Max(float64(x), float64(min)), float64(max)))
+}
+
+// Smoothly goes from 0 to 1 as x goes from edge0 to edge1
+func smoothstep(edge0, edge1, x float32) float32 {
+	t := clamp((x-edge0)/(edge1-edge0), 0, 1)
+	return t * t * (3.0 - 2.0*t)
+}
@@ -0,0 +1,161 @@
+package generation
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestPowerLaw(t *testing.T) {
+	for _, exponent := range []float64{0, 0.5, 1, 1.8, 3} {
+		last := 0.0
+		for u := 0.0; u <= 1.0; u += 0.125 {
+			r := powerLaw(u, 0.02, 0.3, exponent)
+			if r < 0.02-1e-9 || r > 0.3+1e-9 {
+				t.Errorf("powerLaw(%v, 0.02, 0.3, %v) is %v, want a radius between 0.02 and 0.3", u, exponent, r)
+			}
+			if r < last {
+				t.Errorf("powerLaw is not increasing with exponent %v at %v", exponent, u)
+			}
+			last = r
+		}
+	}
+
+	// An exponent of 0.5 from 0 is the same as the squared random numbers craters used to have
+	for u := 0.0; u <= 1.0; u += 0.125 {
+		if r := powerLaw(u, 0, 0.25, 0.5); math.Abs(r-u*u*0.25) > 1e-9 {
+			t.Errorf("powerLaw(%v, 0, 0.25, 0.5) is %v, want %v", u, r, u*u*0.25)
+		}
+	}
+
+	if r := powerLaw(0.3, 0.1, 0.1, 2); r != 0.1 {
+		t.Errorf("powerLaw with equal min and max is %v, want 0.1", r)
+	}
+}
+
+func TestCraterSizeDistribution(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	settings := CraterSettings{Count: 2000, MinRadius: 0.02, MaxRadius: 0.3, SizeExponent: 2}
+
+	small := 0
+	for _, crater := range genCraters(&settings, rng) {
+		if crater.Radius < 0.02 || crater.Radius > 0.3 {
+			t.Fatalf("crater radius %v is outside of 0.02 and 0.3", crater.Radius)
+		}
+		if crater.Radius < 0.04 {
+			small++
+		}
+	}
+
+	// Half of the craters between 0.02 and 0.3 are smaller than about 0.0375 with an exponent of 2
+	if small < 900 {
+		t.Errorf("%d of 2000 craters are smaller than 0.04, want most of them", small)
+	}
+}
+
+func TestCraterSettingsValidate(t *testing.T) {
+	for _, c := range []struct {
+		settings CraterSettings
+		want     string
+	}{
+		{CraterSettings{MinRadius: -0.1, Smoothness: 0.3}, "min radius"},
+		{CraterSettings{MinRadius: 0.2, MaxRadius: 0.1, Smoothness: 0.3}, "max radius"},
+		{CraterSettings{Count: 1, MaxRadius: 0.1, SizeExponent: 2, Smoothness: 0.3}, "size exponent"},
+		{CraterSettings{RimNoise: 0.5, Smoothness: 0.3}, "rim noise"},
+		{CraterSettings{EjectaWidth: -1, Smoothness: 0.3}, "ejecta width"},
+	} {
+		err := c.settings.validate()
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%+v returned %v, want an error containing %q", c.settings, err, c.want)
+		}
+	}
+
+	settings := CraterSettingsOf(DefaultMoon().Shape)
+	if err := settings.validate(); err != nil {
+		t.Errorf("the moon craters are invalid: %v", err)
+	}
+}
+
+func TestCraterReach(t *testing.T) {
+	settings := CraterSettingsOf(DefaultMoon().Shape)
+	crater := Crater{mgl32.Vec3{0, 0, 1}, 0.2}
+	field := newCraterField(&settings, Simplex{}, []Crater{crater})
+
+	// Every part of the crater, the noisy rim and the ejecta stays within its reach
+	reach := crater.Radius * settings.reach() * 1.001
+	// The angle on the sphere between the center of the crater and points just past its reach
+	theta := 2 * math.Asin(float64(reach)/2)
+	for angle := 0.0; angle < 2*math.Pi; angle += 0.1 {
+		direction := mgl32.Vec3{float32(math.Cos(angle)), float32(math.Sin(angle)), 0}
+		point := crater.Position.Mul(float32(math.Cos(theta))).Add(direction.Mul(float32(math.Sin(theta))))
+		if h := field.height(point); h != 0 {
+			t.Errorf("height past the reach of the crater is %v, want 0", h)
+		}
+	}
+}
+
+func TestNewerCratersEraseOlder(t *testing.T) {
+	settings := CraterSettingsOf(DefaultMoon().Shape)
+	settings.RimNoise = 0
+	settings.EjectaHeight = 0
+	settings.PeakHeight = 0
+
+	large := Crater{mgl32.Vec3{0, 0, 1}, 0.3}
+	small := Crater{mgl32.Vec3{0.1, 0, 1}.Normalize(), 0.05}
+
+	// A small crater inside of a large crater is carved into the floor of the large crater
+	field := newCraterField(&settings, Simplex{}, []Crater{large, small})
+	floor := newCraterField(&settings, Simplex{}, []Crater{large}).height(small.Position)
+	if h := field.height(small.Position); h >= floor {
+		t.Errorf("height in a small crater is %v, want it below the floor of the large crater at %v", h, floor)
+	}
+
+	// A large crater on top of a small crater wipes it away
+	field = newCraterField(&settings, Simplex{}, []Crater{small, large})
+	if h := field.height(small.Position); math.Abs(float64(h-floor)) > 1e-5 {
+		t.Errorf("height where an erased crater was is %v, want the floor of the large crater at %v", h, floor)
+	}
+}
+
+func TestRandomPointOnSphereIsEven(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const n = 20000
+
+	// The caps above 60 degrees of latitude cover 1 - sin(60) of the sphere, about 13.4%
+	caps := 0
+	for i := 0; i < n; i++ {
+		p := randomPointOnSphere(rng)
+		if math.Abs(float64(p.Len()-1)) > 1e-5 {
+			t.Fatalf("point %v is not on the unit sphere", p)
+		}
+		if math.Abs(float64(p.Y())) > math.Sin(math.Pi/3) {
+			caps++
+		}
+	}
+
+	if share := float64(caps) / n; share < 0.12 || share > 0.15 {
+		t.Errorf("%.1f%% of the points are near the poles, want about 13.4%%", share*100)
+	}
+}
+
+func TestCraterSpacing(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	settings := CraterSettings{Count: 100, MinRadius: 0.05, MaxRadius: 0.1, SizeExponent: 2, Spacing: 1}
+
+	craters := genCraters(&settings, rng)
+	if len(craters) != 100 {
+		t.Fatalf("got %d craters, want 100", len(craters))
+	}
+
+	// There is plenty of room for these craters, so none of them overlap
+	for i, a := range craters {
+		for _, b := range craters[:i] {
+			if distance := a.Position.Sub(b.Position).Len(); distance < settings.Spacing*(a.Radius+b.Radius) {
+				t.Errorf("craters of radius %v and %v are %v apart, want at least the sum of their radii", a.Radius, b.Radius, distance)
+			}
+		}
+	}
+}
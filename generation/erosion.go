@@ -0,0 +1,183 @@
+package generation
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The most vertices a drop of rain flows over before it is dropped
+const maxDropSteps = 128
+
+// Drops with less water than this have dried up
+const minDropWater = 0.01
+
+// The vertices next to every vertex of a mesh, the neighbours of vertex i are neighbours[offsets[i]:offsets[i+1]]
+type vertexGraph struct {
+	offsets    []int32
+	neighbours []uint32
+}
+
+/*
+ErodeTerrain weathers the terrain of a planet mesh by letting rain carve valleys and letting steep slopes crumble,
+as described by the erosion settings of a planet shape
+
+Parameters:
+- points: the points of the planet after GenTerrain, moved in place
+- indices: the indices of the triangles of the planet
+- shape: the planet shape struct containing the erosion settings
+
+Example usage:
+
+	points, indices := genOctahedron(100)
+	normalizePointDistances(points)
+
+	settings := DefaultEarth()
+	settings.Shape.ErosionIterations = 4
+
+	err := GenTerrain(points, settings.Shape)
+	ErodeTerrain(points, indices, settings.Shape)
+*/
+func ErodeTerrain(points []mgl32.Vec3, indices []uint32, shape PlanetShape) {
+	if shape.ErosionIterations == 0 && shape.ThermalIterations == 0 {
+		return
+	}
+
+	graph := newVertexGraph(len(points), indices)
+
+	directions := make([]mgl32.Vec3, len(points))
+	heights := make([]float32, len(points))
+	for i, point := range points {
+		heights[i] = point.Len()
+		directions[i] = point.Mul(1.0 / heights[i])
+	}
+
+	// Draw the rain from its own stream, so erosion never moves the craters
+	rng := rand.New(rand.NewSource(shape.Seed ^ 0x5eed))
+
+	for i := uint32(0); i < shape.ErosionIterations; i++ {
+		// Every round of rain drops one drop on every vertex, in a random order
+		for _, start := range rng.Perm(len(points)) {
+			dropRain(&graph, heights, uint32(start), &shape)
+		}
+	}
+
+	for i := uint32(0); i < shape.ThermalIterations; i++ {
+		crumble(&graph, directions, heights, &shape)
+	}
+
+	for i := range points {
+		points[i] = directions[i].Mul(heights[i])
+	}
+}
+
+// Finds the vertices that share an edge with every vertex
+func newVertexGraph(numVertices int, indices []uint32) vertexGraph {
+	lists := make([][]uint32, numVertices)
+	for i := 0; i < len(indices); i += 3 {
+		for j := 0; j < 3; j++ {
+			a, b := indices[i+j], indices[i+(j+1)%3]
+			lists[a] = append(lists[a], b)
+			lists[b] = append(lists[b], a)
+		}
+	}
+
+	graph := vertexGraph{make([]int32, numVertices+1), []uint32{}}
+	for i, list := range lists {
+		// Every edge is shared by two triangles, keep each neighbour once
+		sort.Slice(list, func(a, b int) bool { return list[a] < list[b] })
+		for j, n := range list {
+			if j == 0 || n != list[j-1] {
+				graph.neighbours = append(graph.neighbours, n)
+			}
+		}
+		graph.offsets[i+1] = int32(len(graph.neighbours))
+	}
+	return graph
+}
+
+// The vertices next to a vertex
+func (g *vertexGraph) next(vertex uint32) []uint32 {
+	return g.neighbours[g.offsets[vertex]:g.offsets[vertex+1]]
+}
+
+// Lets a drop of rain flow downhill from a vertex, picking up sediment on steep ground and leaving it on flat ground
+func dropRain(graph *vertexGraph, heights []float32, vertex uint32, shape *PlanetShape) {
+	water := shape.ErosionRain
+	sediment := float32(0.0)
+
+	for step := 0; step < maxDropSteps && water >= minDropWater; step++ {
+		// Rivers end in the sea, where they leave their sediment as a fan
+		if heights[vertex] < 1.0 {
+			heights[vertex] += float32(math.Min(float64(sediment), float64(1.0-heights[vertex])))
+			return
+		}
+
+		// Flow to the lowest neighbour
+		lowest := vertex
+		for _, n := range graph.next(vertex) {
+			if heights[n] < heights[lowest] {
+				lowest = n
+			}
+		}
+
+		drop := heights[vertex] - heights[lowest]
+		if lowest == vertex {
+			// Fill the pit up to the lowest neighbour, the rest of the sediment is lost with the water
+			rim := float32(math.Inf(1))
+			for _, n := range graph.next(vertex) {
+				rim = float32(math.Min(float64(rim), float64(heights[n])))
+			}
+			heights[vertex] += float32(math.Min(float64(sediment), float64(rim-heights[vertex])))
+			return
+		}
+
+		// Drops that fall further move faster and carry more
+		capacity := drop * water * shape.ErosionCapacity
+
+		if sediment > capacity {
+			// Too much sediment for the slope, leave some of it behind
+			deposit := (sediment - capacity) * shape.ErosionDeposition
+			sediment -= deposit
+			heights[vertex] += deposit
+		} else {
+			// Never dig deeper than the next vertex, that would make a pit
+			erode := float32(math.Min(float64((capacity-sediment)*shape.ErosionRate), float64(drop)))
+			sediment += erode
+			heights[vertex] -= erode
+		}
+
+		vertex = lowest
+		water *= 1.0 - shape.ErosionEvaporation
+	}
+
+	// The drop dried up, so its sediment stays where it is
+	heights[vertex] += sediment
+}
+
+// Moves material from every vertex to the neighbours it is too steep to, forming slopes of loose rock
+func crumble(graph *vertexGraph, directions []mgl32.Vec3, heights []float32, shape *PlanetShape) {
+	changes := make([]float32, len(heights))
+
+	for v := range heights {
+		neighbours := graph.next(uint32(v))
+		for _, n := range neighbours {
+			distance := directions[v].Sub(directions[n]).Len()
+			excess := heights[v] - heights[n] - shape.ThermalTalus*distance
+			if excess <= 0 {
+				continue
+			}
+
+			// Share the material between the neighbours, so a peak never falls below them
+			move := shape.ThermalRate * excess * 0.5 / float32(len(neighbours))
+			changes[v] -= move
+			changes[n] += move
+		}
+	}
+
+	for i := range heights {
+		heights[i] += changes[i]
+	}
+}
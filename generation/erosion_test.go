@@ -0,0 +1,106 @@
+package generation
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVertexGraph(t *testing.T) {
+	points, indices := genOctahedron(4)
+	graph := newVertexGraph(len(points), indices)
+
+	for v := range points {
+		neighbours := graph.next(uint32(v))
+		// The corners of the octahedron have 4 neighbours and every other vertex has 6
+		if len(neighbours) != 4 && len(neighbours) != 6 {
+			t.Errorf("vertex %d has %d neighbours, want 4 or 6", v, len(neighbours))
+		}
+		for _, n := range neighbours {
+			if int(n) == v {
+				t.Errorf("vertex %d is its own neighbour", v)
+			}
+		}
+	}
+}
+
+// Generates the heights of a small eroded earth, and the heights before erosion
+func erodedEarth(t *testing.T, iterations, thermalIterations uint32) (before, after []float32) {
+	t.Helper()
+
+	shape := DefaultEarth().Shape
+	shape.ErosionIterations = iterations
+	shape.ThermalIterations = thermalIterations
+
+	points, indices := genOctahedron(40)
+	normalizePointDistances(points)
+	if err := GenTerrain(points, shape); err != nil {
+		t.Fatal(err)
+	}
+
+	before = make([]float32, len(points))
+	for i, point := range points {
+		before[i] = point.Len()
+	}
+	ErodeTerrain(points, indices, shape)
+
+	after = make([]float32, len(points))
+	for i, point := range points {
+		after[i] = point.Len()
+	}
+	return before, after
+}
+
+func TestHydraulicErosion(t *testing.T) {
+	before, after := erodedEarth(t, 4, 0)
+	_, again := erodedEarth(t, 4, 0)
+
+	sumBefore, sumAfter := 0.0, 0.0
+	changed := false
+	for i := range before {
+		sumBefore += float64(before[i])
+		sumAfter += float64(after[i])
+		changed = changed || before[i] != after[i]
+
+		if after[i] != again[i] {
+			t.Fatalf("vertex %d is %v in one erosion and %v in another, want the same terrain from the same seed", i, after[i], again[i])
+		}
+	}
+
+	if !changed {
+		t.Error("erosion did not change the terrain")
+	}
+	// Rain only moves terrain around and washes it into the sea, it never makes more
+	if sumAfter > sumBefore+1e-3 {
+		t.Errorf("the terrain grew from %v to %v", sumBefore, sumAfter)
+	}
+}
+
+func TestThermalErosion(t *testing.T) {
+	before, after := erodedEarth(t, 0, 20)
+
+	// Crumbling moves material without losing any
+	sumBefore, sumAfter := 0.0, 0.0
+	highest, highestAfter := float32(0), float32(0)
+	for i := range before {
+		sumBefore += float64(before[i])
+		sumAfter += float64(after[i])
+		highest = float32(math.Max(float64(highest), float64(before[i])))
+		highestAfter = float32(math.Max(float64(highestAfter), float64(after[i])))
+	}
+
+	if math.Abs(sumAfter-sumBefore) > 1e-2 {
+		t.Errorf("the terrain changed from %v to %v, want material to only move", sumBefore, sumAfter)
+	}
+	if highestAfter > highest {
+		t.Errorf("the highest point rose from %v to %v, want steep peaks to crumble", highest, highestAfter)
+	}
+}
+
+func TestErosionOff(t *testing.T) {
+	before, after := erodedEarth(t, 0, 0)
+	for i := range before {
+		if before[i] != after[i] {
+			t.Fatalf("vertex %d moved from %v to %v without erosion", i, before[i], after[i])
+		}
+	}
+}
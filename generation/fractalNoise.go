@@ -0,0 +1,105 @@
+package generation
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// FractalSettings describes how the octaves of fbm and ridge noise are layered on top of each other.
+// Zero values are the defaults of DetailedNoise: 5 octaves that each double the frequency and halve the amplitude.
+type FractalSettings struct {
+	Octaves     uint32  // more octaves add finer detail but take longer to generate
+	Lacunarity  float32 // how much the frequency is multiplied by from one octave to the next
+	Persistence float32 // how much the amplitude is multiplied by from one octave to the next
+	Rotate      bool    // rotates and offsets every octave so that the grids of the noise do not line up
+}
+
+// The most octaves a fractal can have, after that the octaves are finer than any mesh
+const maxOctaves = 16
+
+// The octaves of a fractal noise, ready to be sampled
+type fractal []octave
+
+type octave struct {
+	frequency float32
+	amplitude float32
+
+	rotated  bool
+	rotation mgl32.Mat3
+	offset   mgl32.Vec3
+}
+
+// Checks that the fractal settings can be used, zero values are allowed since they pick the defaults
+func (s FractalSettings) validate() error {
+	if s.Octaves > maxOctaves {
+		return fmt.Errorf("octaves must be at most %d, got %d", maxOctaves, s.Octaves)
+	}
+	if s.Lacunarity < 0 {
+		return fmt.Errorf("lacunarity must not be negative, got %g", s.Lacunarity)
+	}
+	if s.Persistence < 0 {
+		return fmt.Errorf("persistence must not be negative, got %g", s.Persistence)
+	}
+	return nil
+}
+
+// Calculates the frequency, amplitude and rotation of every octave
+func newFractal(s FractalSettings, amplitude, frequency float32) fractal {
+	octaves, lacunarity, persistence := int(s.Octaves), s.Lacunarity, s.Persistence
+	if octaves == 0 {
+		octaves = 5
+	}
+	if lacunarity == 0 {
+		lacunarity = 2.0
+	}
+	if persistence == 0 {
+		persistence = 0.5
+	}
+
+	f := make(fractal, octaves)
+	axis := mgl32.Vec3{1, 2, 3}.Normalize()
+
+	for i := range f {
+		f[i].frequency = frequency
+		f[i].amplitude = amplitude
+
+		// Turn every octave by the golden angle and move it far away from the last,
+		// the first octave is left alone so that it matches unrotated noise
+		if s.Rotate && i > 0 {
+			f[i].rotated = true
+			f[i].rotation = mgl32.HomogRotate3D(float32(i)*2.3999632, axis).Mat3()
+			f[i].offset = mgl32.Vec3{17.31, -31.17, 23.57}.Mul(float32(i))
+		}
+
+		frequency *= lacunarity
+		amplitude *= persistence
+	}
+
+	return f
+}
+
+// Adds together every octave of the noise at a point
+func (f fractal) sample(noise Noise, point mgl32.Vec3) float32 {
+	noiseHeight := float32(0.0)
+
+	for i := range f {
+		o := &f[i]
+		p := point.Mul(o.frequency)
+		if o.rotated {
+			p = o.rotation.Mul3x1(p).Add(o.offset)
+		}
+		noiseHeight += noise.Sample(p.X(), p.Y(), p.Z()) * o.amplitude
+	}
+
+	return noiseHeight
+}
+
+// Like sample, but uses negative absolute values to form sharp edges
+func (f fractal) ridge(noise Noise, point mgl32.Vec3) float32 {
+	if len(f) == 0 {
+		return 0
+	}
+	return f[0].amplitude*0.5 - float32(math.Abs(float64(f.sample(noise, point))))
+}
@@ -0,0 +1,109 @@
+package generation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFractalDefaults(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// Zero settings are the same fractal as DetailedNoise
+	a := newFractal(FractalSettings{}, 1.5, 2.0)
+	b := newFractal(FractalSettings{5, 2.0, 0.5, false}, 1.5, 2.0)
+
+	for i := 0; i < 1000; i++ {
+		p := randomPoint(rng, 1).Normalize()
+		if va, vb := a.sample(Simplex{}, p), b.sample(Simplex{}, p); va != vb {
+			t.Fatalf("default fractal is %v at %v, want %v", va, p, vb)
+		}
+	}
+}
+
+func TestFractalOctaves(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	// A single octave is a single sample of the noise
+	single := newFractal(FractalSettings{1, 2.0, 0.5, true}, 2.0, 3.0)
+	for i := 0; i < 1000; i++ {
+		p := randomPoint(rng, 1).Normalize()
+		want := SimpleNoise(p, 2.0, 3.0)
+		if v := single.sample(Simplex{}, p); math.Abs(float64(v-want)) > 1e-6 {
+			t.Fatalf("one octave is %v at %v, want %v", v, p, want)
+		}
+	}
+}
+
+func TestFractalRange(t *testing.T) {
+	for _, settings := range []FractalSettings{
+		{8, 1.9, 0.6, false},
+		{8, 1.9, 0.6, true},
+		{3, 3.0, 0.25, true},
+		{16, 2.0, 1.0, false},
+	} {
+		rng := rand.New(rand.NewSource(3))
+		f := newFractal(settings, 1.0, 2.0)
+
+		// The octaves add up to at most the sum of their amplitudes
+		bound := 0.0
+		for i := 0; i < int(settings.Octaves); i++ {
+			bound += math.Pow(float64(settings.Persistence), float64(i))
+		}
+
+		for i := 0; i < 2000; i++ {
+			p := randomPoint(rng, 1).Normalize()
+			if v := f.sample(Simplex{}, p); math.Abs(float64(v)) > bound+1e-5 {
+				t.Fatalf("%+v is %v at %v, want a value between %v and %v", settings, v, p, -bound, bound)
+			}
+			if v := f.ridge(Simplex{}, p); v > 0.5 || float64(v) < 0.5-bound-1e-5 {
+				t.Fatalf("%+v ridge is %v at %v, want a value between %v and 0.5", settings, v, p, 0.5-bound)
+			}
+		}
+	}
+}
+
+func TestFractalRotation(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	a := newFractal(FractalSettings{4, 2.0, 0.5, false}, 1.0, 1.0)
+	b := newFractal(FractalSettings{4, 2.0, 0.5, true}, 1.0, 1.0)
+
+	same := 0
+	for i := 0; i < 1000; i++ {
+		p := randomPoint(rng, 1).Normalize()
+		if a.sample(Simplex{}, p) == b.sample(Simplex{}, p) {
+			same++
+		}
+	}
+	if same > 10 {
+		t.Errorf("rotating the octaves changed the noise at only %d of 1000 points", 1000-same)
+	}
+}
+
+func TestFractalValidate(t *testing.T) {
+	for _, settings := range []FractalSettings{{maxOctaves + 1, 2, 0.5, false}, {5, -1, 0.5, false}, {5, 2, -0.5, false}} {
+		if err := settings.validate(); err == nil {
+			t.Errorf("%+v is valid, want an error", settings)
+		}
+	}
+	if err := (FractalSettings{}).validate(); err != nil {
+		t.Errorf("zero settings are invalid: %v", err)
+	}
+}
+
+func BenchmarkFractal(b *testing.B) {
+	for _, octaves := range []uint32{1, 3, 5, 8} {
+		for _, rotate := range []bool{false, true} {
+			name := fmt.Sprintf("octaves=%d/rotate=%v", octaves, rotate)
+			b.Run(name, func(b *testing.B) {
+				f := newFractal(FractalSettings{octaves, 2.0, 0.5, rotate}, 1.0, 1.0)
+				p := randomPoint(rand.New(rand.NewSource(5)), 1).Normalize()
+
+				for i := 0; i < b.N; i++ {
+					f.sample(Simplex{}, p)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package generation
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// River is a line of vertices that water flows along, from its source to where it ends in the sea, a lake or a larger river
+type River struct {
+	Points []mgl32.Vec3 // the bed of the river on the surface of the planet, where 1.0 is sea level
+	Flow   []float32    // the part of the planet surface that drains through every point
+}
+
+// Hydrology holds the rivers and lakes of a planet mesh
+type Hydrology struct {
+	Rivers []River
+
+	// The height of the water surface at every vertex of the mesh, 0 where the vertex is not in a lake
+	LakeLevels []float32
+
+	// A mesh of the lake surfaces and the rivers, with the same layout as the mesh from GenPlanet
+	WaterVertices []float32
+	WaterIndices  []uint32
+}
+
+// How much higher than the river bed the river surface is, relative to the river depth
+const riverSurface = 0.6
+
+// Flood fills lower than this above the ground are not lakes, but flats that water runs over
+const minLakeDepth = 1e-5
+
+/*
+GenHydrology finds where water flows on a planet mesh, carves rivers from high ground to the sea and
+fills depressions with lakes up to the height where they spill over, as described by the river settings of a planet shape
+
+Parameters:
+- points: the points of the planet after GenTerrain, moved in place where rivers are carved
+- indices: the indices of the triangles of the planet
+- shape: the planet shape struct containing the river settings
+
+Returns:
+- water: the rivers and lakes of the planet, empty if the shape has no rivers
+
+Example usage:
+
+	points, indices := genOctahedron(100)
+	normalizePointDistances(points)
+
+	settings := DefaultEarth()
+	settings.Shape.RiverMinArea = 0.002
+
+	err := GenTerrain(points, settings.Shape)
+	water := GenHydrology(points, indices, settings.Shape)
+	fmt.Println(len(water.Rivers), "rivers")
+*/
+func GenHydrology(points []mgl32.Vec3, indices []uint32, shape PlanetShape) Hydrology {
+	if shape.RiverMinArea <= 0 {
+		return Hydrology{}
+	}
+
+	graph := newVertexGraph(len(points), indices)
+
+	directions := make([]mgl32.Vec3, len(points))
+	heights := make([]float32, len(points))
+	for i, point := range points {
+		heights[i] = point.Len()
+		directions[i] = point.Mul(1.0 / heights[i])
+	}
+
+	levels, downstream, order := floodFill(&graph, heights)
+
+	// Every vertex drains an equal part of the surface, which is passed on to the vertices downstream
+	flow := make([]float32, len(points))
+	for i := len(order) - 1; i >= 0; i-- {
+		v := order[i]
+		flow[v] += 1.0 / float32(len(points))
+		if downstream[v] >= 0 {
+			flow[downstream[v]] += flow[v]
+		}
+	}
+
+	water := Hydrology{LakeLevels: make([]float32, len(points))}
+	isRiver := make([]bool, len(points))
+	for v := range points {
+		switch {
+		case heights[v] < 1.0:
+			// The sea
+		case levels[v]-heights[v] > minLakeDepth:
+			water.LakeLevels[v] = levels[v]
+		case flow[v] >= shape.RiverMinArea:
+			isRiver[v] = true
+		}
+	}
+
+	// Carve the rivers from the sea upwards, so a river is never carved deeper than where it flows to
+	depths := make([]float32, len(points))
+	for _, v := range order {
+		if !isRiver[v] {
+			continue
+		}
+		// Rivers start out as shallow streams and grow deeper with every stream that joins them
+		depth := shape.RiverDepth * (1.0 - shape.RiverMinArea/flow[v])
+		carved := heights[v] - depth
+		if down := downstream[v]; down >= 0 {
+			carved = float32(math.Max(float64(carved), float64(math.Min(float64(heights[down]), float64(heights[v])))))
+		}
+		depths[v] = heights[v] - carved
+		heights[v] = carved
+	}
+
+	for i := range points {
+		points[i] = directions[i].Mul(heights[i])
+	}
+
+	rivers := traceRivers(isRiver, downstream)
+	for _, river := range rivers {
+		r := River{make([]mgl32.Vec3, len(river)), make([]float32, len(river))}
+		for i, v := range river {
+			r.Points[i] = points[v]
+			r.Flow[i] = flow[v]
+		}
+		water.Rivers = append(water.Rivers, r)
+	}
+	water.WaterVertices, water.WaterIndices = waterMesh(indices, directions, heights, depths, water.LakeLevels, rivers, flow, &shape)
+
+	return water
+}
+
+// A vertex waiting to be flooded, with the height the water reaches there
+type floodVertex struct {
+	vertex uint32
+	level  float32
+}
+
+type floodQueue []floodVertex
+
+func (q floodQueue) Len() int { return len(q) }
+func (q floodQueue) Less(i, j int) bool {
+	// Break ties by vertex, so the flood is the same on every run
+	if q[i].level == q[j].level {
+		return q[i].vertex < q[j].vertex
+	}
+	return q[i].level < q[j].level
+}
+func (q floodQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *floodQueue) Push(x interface{}) { *q = append(*q, x.(floodVertex)) }
+func (q *floodQueue) Pop() interface{} {
+	old := *q
+	x := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return x
+}
+
+/*
+Floods the terrain from the sea inwards, always from the lowest flooded vertex, so every depression fills up to
+the height where it spills over. Planets without a sea are flooded from their lowest vertex.
+
+Returns:
+- levels: the height of the water at every vertex, which is the height of the vertex where it is not in a lake
+- downstream: the vertex that water flows to from every vertex, -1 where it ends in the sea
+- order: the vertices in the order they were flooded, every vertex comes after the vertex it flows to
+*/
+func floodFill(graph *vertexGraph, heights []float32) (levels []float32, downstream []int32, order []uint32) {
+	levels = make([]float32, len(heights))
+	downstream = make([]int32, len(heights))
+	order = make([]uint32, 0, len(heights))
+	flooded := make([]bool, len(heights))
+	queue := &floodQueue{}
+
+	lowest := 0
+	for v, h := range heights {
+		if h < heights[lowest] {
+			lowest = v
+		}
+		if h < 1.0 {
+			heap.Push(queue, floodVertex{uint32(v), h})
+			flooded[v] = true
+			downstream[v] = -1
+		}
+	}
+	if queue.Len() == 0 {
+		heap.Push(queue, floodVertex{uint32(lowest), heights[lowest]})
+		flooded[lowest] = true
+		downstream[lowest] = -1
+	}
+
+	for queue.Len() > 0 {
+		next := heap.Pop(queue).(floodVertex)
+		levels[next.vertex] = next.level
+		order = append(order, next.vertex)
+
+		for _, n := range graph.next(next.vertex) {
+			if flooded[n] {
+				continue
+			}
+			flooded[n] = true
+			downstream[n] = int32(next.vertex)
+			heap.Push(queue, floodVertex{n, float32(math.Max(float64(heights[n]), float64(next.level)))})
+		}
+	}
+
+	return levels, downstream, order
+}
+
+// Follows every river from its source until it ends in the sea, a lake or another river, as lists of vertices
+func traceRivers(isRiver []bool, downstream []int32) [][]int32 {
+	// Sources are river vertices that no other river vertex flows to
+	fed := make([]bool, len(isRiver))
+	for v, river := range isRiver {
+		if river && downstream[v] >= 0 {
+			fed[downstream[v]] = true
+		}
+	}
+
+	traced := make([]bool, len(isRiver))
+	rivers := [][]int32{}
+	trace := func(source int32) {
+		river := []int32{}
+		for v := source; v >= 0; v = downstream[v] {
+			river = append(river, v)
+
+			// The river ends where it reaches water or joins a river that is already traced
+			if !isRiver[v] || traced[v] {
+				break
+			}
+			traced[v] = true
+		}
+		if len(river) > 1 {
+			rivers = append(rivers, river)
+		}
+	}
+
+	// Rivers that flow out of lakes are fed by the lake and start where it spills over
+	for v, river := range isRiver {
+		if river && !fed[v] {
+			trace(int32(v))
+		}
+	}
+
+	return rivers
+}
+
+// Builds flat lake surfaces over the lakes and ribbons of water along the rivers
+func waterMesh(indices []uint32, directions []mgl32.Vec3, heights, depths, lakeLevels []float32, rivers [][]int32, flow []float32, shape *PlanetShape) ([]float32, []uint32) {
+	vertices := []float32{}
+	waterIndices := []uint32{}
+
+	addVertex := func(point, normal mgl32.Vec3) {
+		waterIndices = append(waterIndices, uint32(len(vertices)/VertexStride))
+		vertices = append(vertices, point.X(), point.Y(), point.Z(), normal.X(), normal.Y(), normal.Z(), float32(NoBiome), 0)
+	}
+	// Adds a triangle that faces away from the planet
+	addTriangle := func(a, b, c, normal mgl32.Vec3) {
+		if b.Sub(a).Cross(c.Sub(a)).Dot(normal) < 0 {
+			b, c = c, b
+		}
+		addVertex(a, normal)
+		addVertex(b, normal)
+		addVertex(c, normal)
+	}
+
+	// Lakes cover every triangle that touches them, the shore hides the water that is under the ground
+	for i := 0; i < len(indices); i += 3 {
+		level := float32(0.0)
+		for _, v := range indices[i : i+3] {
+			level = float32(math.Max(float64(level), float64(lakeLevels[v])))
+		}
+		if level == 0 {
+			continue
+		}
+		a, b, c := directions[indices[i]], directions[indices[i+1]], directions[indices[i+2]]
+		addTriangle(a.Mul(level), b.Mul(level), c.Mul(level), a.Add(b).Add(c).Normalize())
+	}
+
+	// Rivers are ribbons along their beds that are wider where more water flows
+	surface := func(v int32) mgl32.Vec3 {
+		return directions[v].Mul(heights[v] + depths[v]*riverSurface)
+	}
+	for _, river := range rivers {
+		for i := 0; i+1 < len(river); i++ {
+			v, next := river[i], river[i+1]
+			a, b := surface(v), surface(next)
+			up := directions[v]
+
+			width := shape.RiverWidth * float32(math.Min(math.Sqrt(float64(flow[v]/shape.RiverMinArea)), 4.0))
+			side := b.Sub(a).Cross(up).Normalize().Mul(width * 0.5)
+
+			addTriangle(a.Sub(side), b.Sub(side), b.Add(side), up)
+			addTriangle(a.Sub(side), b.Add(side), a.Add(side), up)
+		}
+	}
+
+	return vertices, waterIndices
+}
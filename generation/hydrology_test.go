@@ -0,0 +1,129 @@
+package generation
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestFloodFillFillsPits(t *testing.T) {
+	// A line of vertices from the sea over a pit and up a hill
+	heights := []float32{0.9, 1.2, 1.1, 1.3, 1.4}
+	graph := vertexGraph{[]int32{0, 1, 3, 5, 7, 8}, []uint32{1, 0, 2, 1, 3, 2, 4, 3}}
+
+	levels, downstream, order := floodFill(&graph, heights)
+
+	wantLevels := []float32{0.9, 1.2, 1.2, 1.3, 1.4}
+	wantDownstream := []int32{-1, 0, 1, 2, 3}
+	for v := range heights {
+		if levels[v] != wantLevels[v] {
+			t.Errorf("level of vertex %d is %v, want %v", v, levels[v], wantLevels[v])
+		}
+		if downstream[v] != wantDownstream[v] {
+			t.Errorf("vertex %d flows to %d, want %d", v, downstream[v], wantDownstream[v])
+		}
+	}
+	if len(order) != len(heights) {
+		t.Errorf("flooded %d vertices, want every one of the %d", len(order), len(heights))
+	}
+}
+
+// Generates a small earth with rivers, and the heights before the rivers were carved
+func riverEarth(t *testing.T, minArea float32) (before []float32, points []mgl32.Vec3, indices []uint32, water Hydrology) {
+	t.Helper()
+
+	shape := DefaultEarth().Shape
+	shape.RiverMinArea = minArea
+
+	points, indices = genOctahedron(60)
+	normalizePointDistances(points)
+	if err := GenTerrain(points, shape); err != nil {
+		t.Fatal(err)
+	}
+
+	before = make([]float32, len(points))
+	for i, point := range points {
+		before[i] = point.Len()
+	}
+	water = GenHydrology(points, indices, shape)
+	return before, points, indices, water
+}
+
+func TestFloodOrder(t *testing.T) {
+	_, points, indices, _ := riverEarth(t, 0.002)
+	graph := newVertexGraph(len(points), indices)
+	heights := make([]float32, len(points))
+	for i, point := range points {
+		heights[i] = point.Len()
+	}
+
+	levels, downstream, order := floodFill(&graph, heights)
+	position := make([]int, len(points))
+	for i, v := range order {
+		position[v] = i
+	}
+
+	for v := range points {
+		if levels[v] < heights[v] {
+			t.Fatalf("vertex %d has water at %v under the ground at %v", v, levels[v], heights[v])
+		}
+		down := downstream[v]
+		if down < 0 {
+			continue
+		}
+		if position[down] > position[v] {
+			t.Fatalf("vertex %d is flooded before the vertex %d it flows to", v, down)
+		}
+		if levels[down] > levels[v] {
+			t.Fatalf("vertex %d at level %v flows up to level %v", v, levels[v], levels[down])
+		}
+	}
+}
+
+func TestRivers(t *testing.T) {
+	before, points, _, water := riverEarth(t, 0.002)
+
+	if len(water.Rivers) == 0 {
+		t.Fatal("no rivers on the earth")
+	}
+	if len(water.WaterIndices) == 0 || len(water.WaterIndices)%3 != 0 {
+		t.Errorf("the water mesh has %d indices, want whole triangles", len(water.WaterIndices))
+	}
+	if len(water.WaterVertices) != len(water.WaterIndices)*VertexStride {
+		t.Errorf("the water mesh has %d floats for %d indices", len(water.WaterVertices), len(water.WaterIndices))
+	}
+
+	// Rivers are only carved into the ground
+	for i, point := range points {
+		if point.Len() > before[i]+1e-6 {
+			t.Fatalf("vertex %d rose from %v to %v", i, before[i], point.Len())
+		}
+	}
+
+	for i, river := range water.Rivers {
+		if len(river.Points) < 2 || len(river.Points) != len(river.Flow) {
+			t.Fatalf("river %d has %d points and %d flows", i, len(river.Points), len(river.Flow))
+		}
+		for j := 1; j < len(river.Points); j++ {
+			if river.Points[j].Len() > river.Points[j-1].Len()+1e-6 {
+				t.Fatalf("river %d flows up from %v to %v", i, river.Points[j-1].Len(), river.Points[j].Len())
+			}
+			if river.Flow[j] < river.Flow[j-1] {
+				t.Fatalf("river %d loses water from %v to %v", i, river.Flow[j-1], river.Flow[j])
+			}
+		}
+	}
+}
+
+func TestRiversOff(t *testing.T) {
+	before, points, _, water := riverEarth(t, 0)
+
+	if len(water.Rivers) != 0 || len(water.WaterIndices) != 0 || water.LakeLevels != nil {
+		t.Error("rivers or lakes without a river area")
+	}
+	for i, point := range points {
+		if point.Len() != before[i] {
+			t.Fatalf("vertex %d moved from %v to %v without rivers", i, before[i], point.Len())
+		}
+	}
+}
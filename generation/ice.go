@@ -0,0 +1,100 @@
+package generation
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// How many degrees of latitude the edges of the ice fade over
+const iceEdgeWidth = 1.5
+
+// The frequency and number of octaves of the noise that moves the edges of the ice
+const (
+	iceNoiseFrequency = 3.0
+	iceNoiseOctaves   = 4
+)
+
+// How far above sea level the sea ice floats, so that the ocean does not hide it
+const seaIceHeight = 0.001
+
+/*
+GenIce finds how much of every point of a planet is covered in ice and snow, as described by the ice settings
+of a planet shape. The ice caps begin at the ice cap latitude at sea level, and the snowline falls from the
+snowline height on the equator to sea level at the ice caps, so mountains closer to the poles are covered further down.
+
+Parameters:
+- directions: points on the unit sphere
+- heights: the distance from the center of the planet to the surface in every direction, where 1.0 is sea level
+- shape: the planet shape struct containing the ice settings
+
+Returns:
+- ice: between 0 where there is no ice and 1 where the ground is covered, nil if the shape has no ice
+
+Example usage:
+
+	directions := []mgl32.Vec3{{0, 1, 0}, {1, 0, 0}}
+	heights, err := GenHeights(directions, DefaultEarth().Shape)
+	ice := GenIce(directions, heights, DefaultEarth().Shape)
+	fmt.Println(ice[0], ice[1]) // the north pole is covered, the equator is not
+*/
+func GenIce(directions []mgl32.Vec3, heights []float32, shape PlanetShape) []float32 {
+	if !shape.HasIce {
+		return nil
+	}
+
+	noise := Simplex{NewNoiseGenerator(shape.Seed ^ 0x1ce)}
+
+	ice := make([]float32, len(directions))
+	for i, direction := range directions {
+		latitude := float32(math.Asin(float64(clamp(direction.Y(), -1, 1)))) * 180.0 / math.Pi
+
+		// How many degrees the point is past the edge of the ice, where climbing up to the snowline
+		// counts as much as going all the way to the ice caps
+		cold := float32(math.Abs(float64(latitude))) - shape.IceCapLatitude
+		if shape.SnowlineHeight > 0 && heights[i] > 1.0 {
+			cold += shape.IceCapLatitude * (heights[i] - 1.0) / shape.SnowlineHeight
+		}
+		cold += iceNoise(noise, direction) * shape.IceRoughness
+
+		ice[i] = smoothstep(-iceEdgeWidth, iceEdgeWidth, cold)
+	}
+
+	return ice
+}
+
+// Fractal noise that moves the edges of the ice, between about -1 and 1
+func iceNoise(noise Noise, direction mgl32.Vec3) float32 {
+	sum, amplitude, frequency := float32(0.0), float32(0.5), float32(iceNoiseFrequency)
+	for octave := 0; octave < iceNoiseOctaves; octave++ {
+		p := direction.Mul(frequency)
+		sum += noise.Sample(p.X(), p.Y(), p.Z()) * amplitude
+		amplitude *= 0.5
+		frequency *= 2.0
+	}
+	return sum
+}
+
+// Finds the ice of every point of a planet mesh, nil if the shape has no ice
+func iceOfPoints(points []mgl32.Vec3, shape PlanetShape) []float32 {
+	if !shape.HasIce {
+		return nil
+	}
+
+	directions := make([]mgl32.Vec3, len(points))
+	heights := make([]float32, len(points))
+	for i, point := range points {
+		heights[i] = point.Len()
+		directions[i] = point.Mul(1.0 / heights[i])
+	}
+	return GenIce(directions, heights, shape)
+}
+
+// Lifts points of the sea that are covered in ice up to the surface, where the sea ice floats
+func floatSeaIce(points []mgl32.Vec3, ice []float32) {
+	for i, point := range points {
+		if ice[i] >= 0.5 && point.Len() < 1.0+seaIceHeight {
+			points[i] = point.Normalize().Mul(1.0 + seaIceHeight)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package generation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// A point on the unit sphere at a latitude in degrees
+func atLatitude(latitude float64) mgl32.Vec3 {
+	radians := latitude * math.Pi / 180.0
+	return mgl32.Vec3{float32(math.Cos(radians)), float32(math.Sin(radians)), 0}
+}
+
+func TestIceCaps(t *testing.T) {
+	shape := DefaultEarth().Shape
+	shape.HasIce = true
+	shape.IceRoughness = 0
+
+	directions := []mgl32.Vec3{atLatitude(90), atLatitude(-80), atLatitude(0), atLatitude(60)}
+	ice := GenIce(directions, []float32{1, 1, 1, 1}, shape)
+
+	want := []float32{1, 1, 0, 0}
+	for i := range directions {
+		if ice[i] != want[i] {
+			t.Errorf("ice at point %d is %v, want %v", i, ice[i], want[i])
+		}
+	}
+
+	// The edge of the ice caps is at the ice cap latitude
+	edge := GenIce([]mgl32.Vec3{atLatitude(float64(shape.IceCapLatitude))}, []float32{1}, shape)
+	if math.Abs(float64(edge[0]-0.5)) > 1e-3 {
+		t.Errorf("ice at the edge of the ice caps is %v, want 0.5", edge[0])
+	}
+}
+
+func TestSnowline(t *testing.T) {
+	shape := DefaultEarth().Shape
+	shape.HasIce = true
+	shape.IceRoughness = 0
+
+	// Above the snowline on the equator, and at half of it half way to the ice caps
+	halfway := atLatitude(float64(shape.IceCapLatitude) * 0.5)
+	directions := []mgl32.Vec3{atLatitude(0), atLatitude(0), halfway, halfway}
+	heights := []float32{1.0 + shape.SnowlineHeight*1.1, 1.0 + shape.SnowlineHeight*0.9, 1.0 + shape.SnowlineHeight*0.6, 1.0 + shape.SnowlineHeight*0.4}
+
+	ice := GenIce(directions, heights, shape)
+	want := []float32{1, 0, 1, 0}
+	for i := range directions {
+		if ice[i] != want[i] {
+			t.Errorf("ice at point %d is %v, want %v", i, ice[i], want[i])
+		}
+	}
+
+	// Without a snowline only the ice caps are covered
+	shape.SnowlineHeight = 0
+	if ice := GenIce(directions[:1], heights[:1], shape); ice[0] != 0 {
+		t.Errorf("mountain without a snowline has ice %v, want 0", ice[0])
+	}
+}
+
+func TestIceOff(t *testing.T) {
+	shape := DefaultEarth().Shape
+	shape.HasIce = false
+
+	if ice := GenIce([]mgl32.Vec3{{0, 1, 0}}, []float32{1}, shape); ice != nil {
+		t.Errorf("planet without ice got %v", ice)
+	}
+
+	shape.Res = 20
+	vertices, _ := genPlanet(t, shape)
+	for i := 7; i < len(vertices); i += VertexStride {
+		if vertices[i] != 0 {
+			t.Fatalf("vertex %d has ice %v on a planet without ice", i/VertexStride, vertices[i])
+		}
+	}
+}
+
+func TestSeaIceFloats(t *testing.T) {
+	shape := DefaultEarth().Shape
+	shape.Res = 30
+
+	vertices, _ := genPlanet(t, shape)
+	covered := 0
+	for i := 0; i < len(vertices); i += VertexStride {
+		position := mgl32.Vec3{vertices[i], vertices[i+1], vertices[i+2]}
+		if ice := vertices[i+7]; ice >= 0.5 {
+			covered++
+			if position.Len() < 1.0 {
+				t.Fatalf("vertex %d is covered in ice %v under the sea", i/VertexStride, ice)
+			}
+		}
+	}
+	if covered == 0 {
+		t.Error("earth has no ice")
+	}
+}
@@ -0,0 +1,182 @@
+package generation
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// PlanetJob is a planet that is generated in the background by a JobQueue
+type PlanetJob struct {
+	Shape PlanetShape
+
+	// Set by the worker before the job is returned from JobQueue.Finished
+	Mesh PlanetMesh
+	Err  error // an error if the generation failed
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	progress Progress
+	fraction atomic.Uint32 // the bits of the last fraction reported
+	done     chan struct{}
+}
+
+// Progress returns how much of the planet has been generated, from 0 to 1
+func (j *PlanetJob) Progress() float32 {
+	return math.Float32frombits(j.fraction.Load())
+}
+
+// Cancel stops the job. A cancelled job is never returned from JobQueue.Finished.
+func (j *PlanetJob) Cancel() {
+	j.cancel()
+}
+
+// Cancelled tells whether the job has been cancelled
+func (j *PlanetJob) Cancelled() bool {
+	return j.ctx.Err() != nil
+}
+
+// Done is closed when the job has finished or stopped after being cancelled
+func (j *PlanetJob) Done() <-chan struct{} {
+	return j.done
+}
+
+// JobQueue generates planets on worker goroutines, and keeps the finished planets until they are collected
+type JobQueue struct {
+	mutex    sync.Mutex
+	ready    *sync.Cond // signalled when a job is submitted or the queue is closed
+	pending  []*PlanetJob
+	finished []*PlanetJob
+	closed   bool
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers sync.WaitGroup
+}
+
+/*
+NewJobQueue starts worker goroutines that generate the planets submitted to the queue, in the order they were submitted
+
+Parameters:
+- workers: how many planets are generated at the same time
+
+Returns:
+- q: the job queue, which has to be closed when it is no longer needed
+
+Example usage:
+
+	q := NewJobQueue(runtime.NumCPU())
+	defer q.Close()
+	job := q.Submit(DefaultEarth().Shape, nil)
+	<-job.Done()
+	vertices := q.Finished()[0].Mesh.Vertices
+*/
+func NewJobQueue(workers int) *JobQueue {
+	q := &JobQueue{}
+	q.ready = sync.NewCond(&q.mutex)
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+
+	for i := 0; i < workers; i++ {
+		q.workers.Add(1)
+		go q.work()
+	}
+
+	return q
+}
+
+/*
+Submit adds a planet to the queue and returns at once. A shape with an invalid terrain graph finishes its job
+with the error when a worker gets to it.
+
+Parameters:
+- shape: the planet shape struct containing a recipe for the planets shape
+- progress: called on a worker goroutine after every step of the generation, may be nil
+
+Returns:
+- job: the job of the planet, which can be cancelled
+*/
+func (q *JobQueue) Submit(shape PlanetShape, progress Progress) *PlanetJob {
+	job := &PlanetJob{Shape: shape, progress: progress, done: make(chan struct{})}
+	job.ctx, job.cancel = context.WithCancel(q.ctx)
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		job.cancel()
+		close(job.done)
+		return job
+	}
+	q.pending = append(q.pending, job)
+	q.ready.Signal()
+
+	return job
+}
+
+// Finished returns the jobs that have finished since it was last called, without waiting for any
+func (q *JobQueue) Finished() []*PlanetJob {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	finished := q.finished
+	q.finished = nil
+	return finished
+}
+
+// Close cancels every job and waits for the workers to stop
+func (q *JobQueue) Close() {
+	q.mutex.Lock()
+	q.closed = true
+	q.cancel()
+	q.ready.Broadcast()
+	q.mutex.Unlock()
+
+	q.workers.Wait()
+}
+
+// Generates the pending jobs until the queue is closed
+func (q *JobQueue) work() {
+	defer q.workers.Done()
+
+	for {
+		q.mutex.Lock()
+		for len(q.pending) == 0 && !q.closed {
+			q.ready.Wait()
+		}
+		if q.closed {
+			// Jobs that never started are cancelled along with the queue
+			for _, job := range q.pending {
+				close(job.done)
+			}
+			q.pending = nil
+			q.mutex.Unlock()
+			return
+		}
+		job := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mutex.Unlock()
+
+		q.run(job)
+	}
+}
+
+// Generates the planet of a job and hands it over to Finished, unless the job was cancelled
+func (q *JobQueue) run(job *PlanetJob) {
+	defer close(job.done)
+
+	job.Mesh, job.Err = GenPlanetMesh(job.ctx, job.Shape, func(fraction float32) {
+		job.fraction.Store(math.Float32bits(fraction))
+		if job.progress != nil {
+			job.progress(fraction)
+		}
+	})
+
+	if job.Cancelled() {
+		return
+	}
+
+	q.mutex.Lock()
+	q.finished = append(q.finished, job)
+	q.mutex.Unlock()
+}
@@ -0,0 +1,136 @@
+package generation
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestJobQueue(t *testing.T) {
+	q := NewJobQueue(2)
+	defer q.Close()
+
+	shape := DefaultEarth().Shape
+	shape.Res = 30
+
+	var mutex sync.Mutex
+	reported := []float32{}
+	job := q.Submit(shape, func(fraction float32) {
+		mutex.Lock()
+		reported = append(reported, fraction)
+		mutex.Unlock()
+	})
+	<-job.Done()
+
+	finished := q.Finished()
+	if len(finished) != 1 || finished[0] != job {
+		t.Fatalf("queue finished %d jobs, want the one submitted", len(finished))
+	}
+	if job.Err != nil {
+		t.Fatal(job.Err)
+	}
+	if len(q.Finished()) != 0 {
+		t.Errorf("finished jobs were returned twice")
+	}
+
+	// The planet is the same as the one generated right away
+	vertices, indices := genPlanet(t, shape)
+	if len(job.Mesh.Vertices) != len(vertices) || len(job.Mesh.Indices) != len(indices) {
+		t.Fatalf("job generated %d vertices and %d indices, want %d and %d",
+			len(job.Mesh.Vertices), len(job.Mesh.Indices), len(vertices), len(indices))
+	}
+	for i := range vertices {
+		if job.Mesh.Vertices[i] != vertices[i] {
+			t.Fatalf("float %d of the vertices is %v, want %v", i, job.Mesh.Vertices[i], vertices[i])
+		}
+	}
+
+	if len(reported) != planetSteps || reported[len(reported)-1] != 1 || job.Progress() != 1 {
+		t.Errorf("progress was reported as %v and is %v, want %d steps up to 1", reported, job.Progress(), planetSteps)
+	}
+	for i := 1; i < len(reported); i++ {
+		if reported[i] <= reported[i-1] {
+			t.Errorf("progress went from %v to %v", reported[i-1], reported[i])
+		}
+	}
+}
+
+func TestJobCancel(t *testing.T) {
+	// One worker busy with the first job leaves the second waiting in the queue
+	q := NewJobQueue(1)
+	shape := DefaultEarth().Shape
+	shape.Res = 30
+
+	first := q.Submit(shape, nil)
+	second := q.Submit(shape, nil)
+	second.Cancel()
+	<-first.Done()
+	<-second.Done()
+
+	finished := q.Finished()
+	if len(finished) != 1 || finished[0] != first {
+		t.Errorf("queue finished %d jobs, want only the one that was not cancelled", len(finished))
+	}
+
+	// Closing the queue cancels the jobs that are left, a full size planet is still being generated when it does
+	third := q.Submit(DefaultEarth().Shape, nil)
+	q.Close()
+	<-third.Done()
+	if !third.Cancelled() || len(q.Finished()) != 0 {
+		t.Errorf("job was not cancelled when the queue was closed")
+	}
+	if late := q.Submit(shape, nil); !late.Cancelled() {
+		t.Errorf("job submitted to a closed queue was not cancelled")
+	}
+}
+
+func TestGenPlanetMeshCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	steps := 0
+	_, err := GenPlanetMesh(ctx, DefaultEarth().Shape, func(fraction float32) {
+		steps++
+		cancel()
+	})
+	if err != context.Canceled || steps != 1 {
+		t.Errorf("cancelled generation returned %v after %d steps, want %v after 1", err, steps, context.Canceled)
+	}
+}
+
+func TestGenPlanetMeshCancelledBefore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	steps := 0
+	_, err := GenPlanetMesh(ctx, DefaultEarth().Shape, func(fraction float32) { steps++ })
+	if err != context.Canceled || steps != 0 {
+		t.Errorf("generation cancelled before it started returned %v after %d steps, want %v after 0", err, steps, context.Canceled)
+	}
+}
+
+func TestJobInvalidTerrain(t *testing.T) {
+	q := NewJobQueue(1)
+	defer q.Close()
+
+	// The worker fails the job with the error of the graph, and goes on with the next job
+	shape := DefaultEarth().Shape
+	shape.Terrain = []TerrainNode{{Name: "a", Type: "plateau"}}
+	job := q.Submit(shape, nil)
+	<-job.Done()
+
+	finished := q.Finished()
+	if len(finished) != 1 || finished[0] != job {
+		t.Fatalf("queue finished %d jobs, want the one submitted", len(finished))
+	}
+	if job.Err == nil || !strings.Contains(job.Err.Error(), "plateau") {
+		t.Errorf("job with an invalid terrain returned %v, want the terrain error", job.Err)
+	}
+
+	shape.Terrain = nil
+	shape.Res = 8
+	next := q.Submit(shape, nil)
+	<-next.Done()
+	if next.Err != nil || len(next.Mesh.Vertices) == 0 {
+		t.Errorf("job after the invalid one returned %d floats and %v", len(next.Mesh.Vertices), next.Err)
+	}
+}
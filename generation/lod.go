@@ -0,0 +1,472 @@
+package generation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// ChunkResolution is the number of quads along the edge of every terrain chunk. It is even, so every other
+// vertex on the edge of a chunk meets a vertex of a chunk one level coarser.
+const ChunkResolution = 32
+
+// MaxLODLevels is the most times the chunks of a face can be split
+const MaxLODLevels = 16
+
+// How close the camera has to be to a chunk, in chunk widths, for it to split into four smaller chunks
+const lodSplitDistance = 2.5
+
+// Chunks merge again a bit further away than they split, so that they do not flicker between two levels
+const lodMergeDistance = lodSplitDistance * 1.25
+
+// How many chunks split every update, every split generates four new chunks
+const lodSplitsPerUpdate = 2
+
+// The faces of the cube that the chunks are laid out on, as a normal and the axes of the u and v coordinates.
+// The u axis crossed with the v axis is the normal, so the triangles of the chunks face away from the planet.
+var cubeFaces = [6][3][3]float64{
+	{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+	{{-1, 0, 0}, {0, 0, 1}, {0, 1, 0}},
+	{{0, 1, 0}, {0, 0, 1}, {1, 0, 0}},
+	{{0, -1, 0}, {1, 0, 0}, {0, 0, 1}},
+	{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}},
+	{{0, 0, -1}, {0, 1, 0}, {1, 0, 0}},
+}
+
+// ChunkKey names a chunk of the planet surface
+type ChunkKey struct {
+	Face  uint8  // the face of the cube the chunk is on
+	Level uint8  // 0 covers a whole face, every level splits the chunks of the level before into four
+	X, Y  uint32 // the position of the chunk on its face, counted in chunks of its level along u and v
+}
+
+// The edges of a chunk, in the order of the bits of LODChunk.Stitch
+const (
+	StitchBottom = 1 << iota // the edge where v is smallest
+	StitchRight              // the edge where u is largest
+	StitchTop                // the edge where v is largest
+	StitchLeft               // the edge where u is smallest
+)
+
+// LODChunk is a chunk to draw, with the edges that meet a coarser chunk and have to be stitched to it
+type LODChunk struct {
+	Key      ChunkKey
+	Stitch   uint8     // a bit for every edge that meets a coarser chunk
+	Vertices []float32 // (ChunkResolution+1)² vertices, row by row along u, laid out like the vertices from GenPlanet
+}
+
+// A chunk in the quadtree of a face
+type lodNode struct {
+	key      ChunkKey
+	vertices []float32
+	children []*lodNode // nil for chunks that are drawn, or the four quarters ordered by X and then Y
+}
+
+// LODTerrain is the surface of a planet as six quadtrees of chunks, one for every face of a cube,
+// that are split into smaller chunks close to the camera and merged again further away
+type LODTerrain struct {
+	shape   PlanetShape
+	terrain *Terrain
+	roots   [6]*lodNode
+}
+
+/*
+NewLODTerrain compiles the terrain of a planet shape and generates the six chunks that cover the whole planet.
+The chunks are sampled from the terrain like the maps, so they have no erosion, rivers or climate.
+
+Parameters:
+- shape: the planet shape struct containing a recipe for the planets shape, and how many times the chunks can be split
+
+Returns:
+- terrain: the planet surface, ready to be updated with the position of the camera
+- err: an error if the terrain graph of the shape is invalid
+
+Example usage:
+
+	shape := DefaultEarth().Shape
+	shape.LODLevels = 8
+	terrain, err := NewLODTerrain(shape)
+	chunks := terrain.Update(mgl32.Vec3{0, 0, 1.1})
+*/
+func NewLODTerrain(shape PlanetShape) (*LODTerrain, error) {
+	if shape.LODLevels > MaxLODLevels {
+		return nil, fmt.Errorf("chunks can be split at most %d times, got %d levels", MaxLODLevels, shape.LODLevels)
+	}
+
+	terrain, err := CompileTerrain(shape)
+	if err != nil {
+		return nil, err
+	}
+	t := &LODTerrain{shape: shape, terrain: terrain}
+
+	nodes := make([]*lodNode, len(t.roots))
+	for face := range nodes {
+		nodes[face] = &lodNode{key: ChunkKey{uint8(face), 0, 0, 0}}
+	}
+	t.genChunks(nodes)
+	copy(t.roots[:], nodes)
+
+	return t, nil
+}
+
+/*
+Update splits the chunks that are close to the camera and merges the chunks that are far from it, and returns
+the chunks to draw. Only a few chunks are split every update, so the terrain gets finer over a few updates.
+Chunks next to each other are never more than one level apart, and the edges of the finer chunks are stitched.
+
+Parameters:
+- camera: the position of the camera relative to the planet, where 1.0 is the radius of the planet
+
+Returns:
+- chunks: the chunks that cover the planet surface
+*/
+func (t *LODTerrain) Update(camera mgl32.Vec3) []LODChunk {
+	t.merge(camera)
+	t.split(camera)
+
+	chunks := []LODChunk{}
+	t.eachLeaf(func(node *lodNode) {
+		chunk := LODChunk{node.key, 0, node.vertices}
+		for edge := 0; edge < 4; edge++ {
+			if t.neighbour(node.key, edge).key.Level < node.key.Level {
+				chunk.Stitch |= 1 << edge
+			}
+		}
+		chunks = append(chunks, chunk)
+	})
+	return chunks
+}
+
+// Merges the chunks whose quarters are all drawn and far enough from the camera, when no finer chunk is next to them
+func (t *LODTerrain) merge(camera mgl32.Vec3) {
+	var visit func(node *lodNode)
+	visit = func(node *lodNode) {
+		if node.children == nil {
+			return
+		}
+		for _, child := range node.children {
+			visit(child)
+		}
+
+		for _, child := range node.children {
+			if child.children != nil {
+				return
+			}
+		}
+		if chunkDistance(node.key, camera) < lodMergeDistance*chunkWidth(node.key.Level) {
+			return
+		}
+		for _, child := range node.children {
+			for edge := 0; edge < 4; edge++ {
+				if t.neighbour(child.key, edge).key.Level > child.key.Level {
+					return
+				}
+			}
+		}
+		node.children = nil
+	}
+
+	for _, root := range t.roots {
+		visit(root)
+	}
+}
+
+// Splits the chunks closest to the camera that are close enough, after splitting the coarser chunks next to them
+func (t *LODTerrain) split(camera mgl32.Vec3) {
+	wanted := []*lodNode{}
+	t.eachLeaf(func(node *lodNode) {
+		if uint32(node.key.Level) < t.shape.LODLevels && chunkDistance(node.key, camera) < lodSplitDistance*chunkWidth(node.key.Level) {
+			wanted = append(wanted, node)
+		}
+	})
+	sort.SliceStable(wanted, func(i, j int) bool {
+		return chunkDistance(wanted[i].key, camera) < chunkDistance(wanted[j].key, camera)
+	})
+
+	for splits := 0; len(wanted) > 0 && splits < lodSplitsPerUpdate; {
+		node := wanted[0]
+		if node.children != nil {
+			wanted = wanted[1:]
+			continue
+		}
+
+		// Splitting a chunk next to a coarser one would leave two levels between them, so that one goes first
+		coarser := []*lodNode{}
+		for edge := 0; edge < 4; edge++ {
+			if n := t.neighbour(node.key, edge); n.key.Level < node.key.Level {
+				coarser = append(coarser, n)
+			}
+		}
+		if len(coarser) > 0 {
+			wanted = append(coarser, wanted...)
+			continue
+		}
+
+		wanted = wanted[1:]
+		node.children = make([]*lodNode, 4)
+		for i := range node.children {
+			key := node.key
+			key.Level++
+			key.X, key.Y = key.X*2+uint32(i%2), key.Y*2+uint32(i/2)
+			node.children[i] = &lodNode{key: key}
+		}
+		t.genChunks(node.children)
+		splits++
+	}
+}
+
+// Calls f with every chunk that is drawn
+func (t *LODTerrain) eachLeaf(f func(node *lodNode)) {
+	var visit func(node *lodNode)
+	visit = func(node *lodNode) {
+		if node.children == nil {
+			f(node)
+			return
+		}
+		for _, child := range node.children {
+			visit(child)
+		}
+	}
+	for _, root := range t.roots {
+		visit(root)
+	}
+}
+
+// Finds the drawn chunk that is next to the middle of an edge of a chunk
+func (t *LODTerrain) neighbour(key ChunkKey, edge int) *lodNode {
+	size := 1.0 / float64(uint32(1)<<key.Level)
+	u, v := (float64(key.X)+0.5)*size, (float64(key.Y)+0.5)*size
+
+	// Step a little past the edge, which might be on another face
+	const step = 1e-9
+	switch edge {
+	case 0:
+		v -= size*0.5 + step
+	case 1:
+		u += size*0.5 + step
+	case 2:
+		v += size*0.5 + step
+	default:
+		u -= size*0.5 + step
+	}
+	face, u, v := cubeFace(key.Face, u, v)
+
+	node := t.roots[face]
+	for node.children != nil {
+		size := 1.0 / float64(uint32(2)<<node.key.Level)
+		x := int(math.Floor(u/size)) - int(node.key.X)*2
+		y := int(math.Floor(v/size)) - int(node.key.Y)*2
+		node = node.children[clampIndex(x)+clampIndex(y)*2]
+	}
+	return node
+}
+
+// Finds the face and the coordinates on it of a point given by the coordinates of another face, that may be outside that face
+func cubeFace(face uint8, u, v float64) (uint8, float64, float64) {
+	if u >= 0 && u < 1 && v >= 0 && v < 1 {
+		return face, u, v
+	}
+
+	point := cubePoint(face, u*2-1, v*2-1)
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if math.Abs(point[i]) > math.Abs(point[axis]) {
+			axis = i
+		}
+	}
+	scale := 1.0 / math.Abs(point[axis])
+
+	for f := range cubeFaces {
+		if cubeFaces[f][0][axis]*point[axis] > 0 {
+			axes := cubeFaces[f]
+			u := (dot3(point, axes[1])*scale + 1) * 0.5
+			v := (dot3(point, axes[2])*scale + 1) * 0.5
+			return uint8(f), math.Min(math.Max(u, 0), math.Nextafter(1, 0)), math.Min(math.Max(v, 0), math.Nextafter(1, 0))
+		}
+	}
+	return face, u, v
+}
+
+func clampIndex(i int) int {
+	if i < 0 {
+		return 0
+	}
+	if i > 1 {
+		return 1
+	}
+	return i
+}
+
+func dot3(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+// The point on a face of the cube at coordinates s and t between -1 and 1
+func cubePoint(face uint8, s, t float64) [3]float64 {
+	axes := cubeFaces[face]
+	return [3]float64{
+		axes[0][0] + axes[1][0]*s + axes[2][0]*t,
+		axes[0][1] + axes[1][1]*s + axes[2][1]*t,
+		axes[0][2] + axes[1][2]*s + axes[2][2]*t,
+	}
+}
+
+// Moves a point of the cube onto the unit sphere. Points are spread more evenly than by normalizing them,
+// and the same point on the edge of two faces always ends up in the same place.
+func cubeToSphere(p [3]float64) mgl32.Vec3 {
+	x2, y2, z2 := p[0]*p[0], p[1]*p[1], p[2]*p[2]
+	return mgl32.Vec3{
+		float32(p[0] * math.Sqrt(1.0-y2*0.5-z2*0.5+y2*z2/3.0)),
+		float32(p[1] * math.Sqrt(1.0-z2*0.5-x2*0.5+z2*x2/3.0)),
+		float32(p[2] * math.Sqrt(1.0-x2*0.5-y2*0.5+x2*y2/3.0)),
+	}.Normalize()
+}
+
+// The width of the chunks of a level on the unit sphere, roughly
+func chunkWidth(level uint8) float32 {
+	return math.Pi * 0.5 / float32(uint32(1)<<level)
+}
+
+// The distance from the camera to the middle of a chunk at sea level
+func chunkDistance(key ChunkKey, camera mgl32.Vec3) float32 {
+	size := 2.0 / float64(uint32(1)<<key.Level)
+	s, t := -1.0+(float64(key.X)+0.5)*size, -1.0+(float64(key.Y)+0.5)*size
+	return cubeToSphere(cubePoint(key.Face, s, t)).Sub(camera).Len()
+}
+
+// Generates the vertices of chunks at the same time
+func (t *LODTerrain) genChunks(nodes []*lodNode) {
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(node *lodNode) {
+			defer wg.Done()
+			node.vertices = t.genChunk(node.key)
+		}(node)
+	}
+	wg.Wait()
+}
+
+// Generates the vertices of a chunk, with the same layout as the vertices from GenPlanet
+func (t *LODTerrain) genChunk(key ChunkKey) []float32 {
+	const n = ChunkResolution
+
+	// A ring of points around the chunk gives the normals on its edges the same neighbours as in the chunks next to it
+	const size = n + 3
+	total := int64(n) << key.Level
+
+	directions := make([]mgl32.Vec3, size*size)
+	for j := 0; j < size; j++ {
+		for i := 0; i < size; i++ {
+			// Coordinates are counted in whole steps of the finest grid, so vertices shared with other chunks are exactly equal
+			u := int64(key.X)*n + int64(i) - 1
+			v := int64(key.Y)*n + int64(j) - 1
+			s := float64(2*u-total) / float64(total)
+			t := float64(2*v-total) / float64(total)
+			directions[j*size+i] = cubeToSphere(cubePoint(key.Face, s, t))
+		}
+	}
+
+	heights := make([]float32, len(directions))
+	nodeHeights := t.terrain.nodeHeights()
+	for i, direction := range directions {
+		heights[i] = 1.0
+		if t.shape.Amplitude != 0.0 {
+			heights[i] = t.terrain.height(direction, nodeHeights)
+		}
+	}
+
+	points := make([]mgl32.Vec3, len(directions))
+	for i, direction := range directions {
+		points[i] = direction.Mul(heights[i])
+	}
+	ice := GenIce(directions, heights, t.shape)
+	if ice != nil {
+		floatSeaIce(points, ice)
+	}
+
+	vertices := make([]float32, 0, (n+1)*(n+1)*VertexStride)
+	for j := 1; j <= n+1; j++ {
+		for i := 1; i <= n+1; i++ {
+			at := j*size + i
+			point := points[at]
+
+			// The normal is found from the points around the vertex, the u axis crossed with the v axis points outwards
+			normal := points[at+1].Sub(points[at-1]).Cross(points[at+size].Sub(points[at-size])).Normalize()
+
+			covered := float32(0.0)
+			if ice != nil {
+				covered = ice[at]
+			}
+			vertices = append(vertices, point.X(), point.Y(), point.Z(), normal.X(), normal.Y(), normal.Z(), float32(NoBiome), covered)
+		}
+	}
+	return vertices
+}
+
+// The indices of every set of stitched edges, generated when they are first needed
+var (
+	chunkIndices     [16][]uint32
+	chunkIndicesOnce [16]sync.Once
+)
+
+/*
+ChunkIndices returns the indices of the triangles of a chunk. On stitched edges every other vertex is skipped,
+so the edge follows the edge of the coarser chunk next to it and leaves no cracks.
+
+Parameters:
+- stitch: a bit for every edge of the chunk that is stitched, as in LODChunk
+
+Returns:
+- indices: the indices of the triangles of the chunk, shared by every chunk so they must not be changed
+
+Example usage:
+
+	for _, chunk := range terrain.Update(camera) {
+		draw(chunk.Vertices, ChunkIndices(chunk.Stitch))
+	}
+*/
+func ChunkIndices(stitch uint8) []uint32 {
+	stitch &= 15
+	chunkIndicesOnce[stitch].Do(func() {
+		chunkIndices[stitch] = genChunkIndices(stitch)
+	})
+	return chunkIndices[stitch]
+}
+
+func genChunkIndices(stitch uint8) []uint32 {
+	const n = ChunkResolution
+
+	// Vertices that are skipped are replaced by the vertex before them on the edge,
+	// which turns some triangles into lines that are not drawn
+	index := func(i, j int) uint32 {
+		switch {
+		case j == 0 && i%2 == 1 && stitch&StitchBottom != 0:
+			i--
+		case i == n && j%2 == 1 && stitch&StitchRight != 0:
+			j--
+		case j == n && i%2 == 1 && stitch&StitchTop != 0:
+			i--
+		case i == 0 && j%2 == 1 && stitch&StitchLeft != 0:
+			j--
+		}
+		return uint32(j*(n+1) + i)
+	}
+
+	indices := make([]uint32, 0, n*n*6)
+	add := func(a, b, c uint32) {
+		if a != b && b != c && c != a {
+			indices = append(indices, a, b, c)
+		}
+	}
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			add(index(i, j), index(i+1, j), index(i+1, j+1))
+			add(index(i, j), index(i+1, j+1), index(i, j+1))
+		}
+	}
+	return indices
+}
@@ -0,0 +1,160 @@
+package generation
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// The position of a vertex of a chunk at i along u and j along v
+func chunkVertex(vertices []float32, i, j int) [3]float32 {
+	at := (j*(ChunkResolution+1) + i) * VertexStride
+	return [3]float32{vertices[at], vertices[at+1], vertices[at+2]}
+}
+
+// Updates a terrain until no more chunks are split or merged
+func settle(t *testing.T, terrain *LODTerrain, camera mgl32.Vec3) []LODChunk {
+	chunks := terrain.Update(camera)
+	for i := 0; i < 100; i++ {
+		next := terrain.Update(camera)
+		if len(next) == len(chunks) {
+			return next
+		}
+		chunks = next
+	}
+	t.Fatalf("terrain did not settle after 100 updates")
+	return nil
+}
+
+// Every vertex on the edge of a chunk that is drawn has to be a vertex of another chunk too, or there is a crack
+func checkSeams(t *testing.T, chunks []LODChunk) {
+	owners := map[[3]float32]map[ChunkKey]bool{}
+	for _, chunk := range chunks {
+		for j := 0; j <= ChunkResolution; j++ {
+			for i := 0; i <= ChunkResolution; i++ {
+				vertex := chunkVertex(chunk.Vertices, i, j)
+				if owners[vertex] == nil {
+					owners[vertex] = map[ChunkKey]bool{}
+				}
+				owners[vertex][chunk.Key] = true
+			}
+		}
+	}
+
+	for _, chunk := range chunks {
+		used := map[uint32]bool{}
+		for _, index := range ChunkIndices(chunk.Stitch) {
+			used[index] = true
+		}
+		for index := range used {
+			i, j := int(index)%(ChunkResolution+1), int(index)/(ChunkResolution+1)
+			if i != 0 && j != 0 && i != ChunkResolution && j != ChunkResolution {
+				continue
+			}
+			if len(owners[chunkVertex(chunk.Vertices, i, j)]) < 2 {
+				t.Fatalf("vertex (%d, %d) on the edge of chunk %+v is not in any other chunk", i, j, chunk.Key)
+			}
+		}
+	}
+}
+
+func TestLODRoots(t *testing.T) {
+	shape := DefaultEarth().Shape
+	terrain, err := NewLODTerrain(shape)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks := terrain.Update(mgl32.Vec3{0, 0, 1.01})
+	if len(chunks) != 6 {
+		t.Fatalf("terrain without levels has %d chunks, want 6", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if chunk.Stitch != 0 {
+			t.Errorf("chunk %+v is stitched without any finer chunks", chunk.Key)
+		}
+		if len(chunk.Vertices) != (ChunkResolution+1)*(ChunkResolution+1)*VertexStride {
+			t.Errorf("chunk %+v has %d floats", chunk.Key, len(chunk.Vertices))
+		}
+	}
+	checkSeams(t, chunks)
+
+	shape.LODLevels = MaxLODLevels + 1
+	if _, err := NewLODTerrain(shape); err == nil {
+		t.Errorf("terrain with %d levels did not fail", shape.LODLevels)
+	}
+}
+
+func TestLODUpdate(t *testing.T) {
+	shape := DefaultEarth().Shape
+	shape.LODLevels = 6
+	terrain, err := NewLODTerrain(shape)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Close to the surface the chunk under the camera is split as far as it can be
+	camera := mgl32.Vec3{0.3, 0.2, 1.0}.Normalize().Mul(1.02)
+	chunks := settle(t, terrain, camera)
+
+	deepest := uint8(0)
+	for _, chunk := range chunks {
+		if chunk.Key.Level > deepest {
+			deepest = chunk.Key.Level
+		}
+	}
+	if deepest != uint8(shape.LODLevels) {
+		t.Errorf("deepest chunk is at level %d, want %d", deepest, shape.LODLevels)
+	}
+
+	// Chunks next to each other are at most one level apart, and the finer one is stitched
+	for _, chunk := range chunks {
+		for edge := 0; edge < 4; edge++ {
+			level := terrain.neighbour(chunk.Key, edge).key.Level
+			if level+1 < chunk.Key.Level || level > chunk.Key.Level+1 {
+				t.Errorf("chunk %+v is next to a chunk at level %d", chunk.Key, level)
+			}
+			if stitched := chunk.Stitch&(1<<edge) != 0; stitched != (level < chunk.Key.Level) {
+				t.Errorf("edge %d of chunk %+v is stitched %v next to a chunk at level %d", edge, chunk.Key, stitched, level)
+			}
+		}
+	}
+	checkSeams(t, chunks)
+
+	// Far away every chunk merges back into the faces
+	chunks = settle(t, terrain, mgl32.Vec3{0, 0, 100})
+	if len(chunks) != 6 {
+		t.Errorf("terrain far from the camera has %d chunks, want 6", len(chunks))
+	}
+}
+
+func TestChunkIndices(t *testing.T) {
+	for stitch := uint8(0); stitch < 16; stitch++ {
+		indices := ChunkIndices(stitch)
+
+		// Twice the area of the triangles, in quads of the chunk grid
+		area := 0
+		for k := 0; k < len(indices); k += 3 {
+			var x, y [3]int
+			for c := range x {
+				x[c], y[c] = int(indices[k+c])%(ChunkResolution+1), int(indices[k+c])/(ChunkResolution+1)
+			}
+			twice := (x[1]-x[0])*(y[2]-y[0]) - (x[2]-x[0])*(y[1]-y[0])
+			if twice <= 0 {
+				t.Fatalf("stitch %04b: triangle %v %v faces inwards or is empty", stitch, x, y)
+			}
+			area += twice
+
+			for c := range x {
+				odd := (y[c] == 0 && stitch&StitchBottom != 0 || y[c] == ChunkResolution && stitch&StitchTop != 0) && x[c]%2 == 1 ||
+					(x[c] == 0 && stitch&StitchLeft != 0 || x[c] == ChunkResolution && stitch&StitchRight != 0) && y[c]%2 == 1
+				if odd {
+					t.Fatalf("stitch %04b: triangle uses vertex (%d, %d) on a stitched edge", stitch, x[c], y[c])
+				}
+			}
+		}
+		if area != 2*ChunkResolution*ChunkResolution {
+			t.Errorf("stitch %04b: triangles cover %v quads, want %d", stitch, float32(area)/2, ChunkResolution*ChunkResolution)
+		}
+	}
+}
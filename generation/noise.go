@@ -1,10 +1,12 @@
-package main
+package generation
 
 import (
 	"math"
+	"math/rand"
 )
 
-var perm = [512]uint8{
+// The permutation table of Ken Perlin's reference implementation, used by Snoise
+var classicPerm = [256]uint8{
 	151, 160, 137, 91, 90, 15,
 	131, 13, 201, 95, 96, 53, 194, 233, 7, 225, 140, 36, 103, 30, 69, 142, 8, 99, 37, 240, 21, 10, 23,
 	190, 6, 148, 247, 120, 234, 75, 0, 26, 197, 62, 94, 252, 219, 203, 117, 35, 11, 32, 57, 177, 33,
@@ -18,7 +20,48 @@ var perm = [512]uint8{
 	251, 34, 242, 193, 238, 210, 144, 12, 191, 179, 162, 241, 81, 51, 145, 235, 249, 14, 239, 107,
 	49, 192, 214, 31, 181, 199, 106, 157, 184, 84, 204, 176, 115, 121, 50, 45, 127, 4, 150, 254,
 	138, 236, 205, 93, 222, 114, 67, 29, 24, 72, 243, 141, 128, 195, 78, 66, 215, 61, 156, 180,
-	151, 160, 137, 91, 90, 15,
+}
+
+// NoiseGenerator samples noise with its own shuffled permutation table, so that every seed gives different noise.
+// It is never changed after it is made and can be shared by any number of goroutines.
+type NoiseGenerator struct {
+	perm [256]uint8
+}
+
+// The generator of Snoise and of noise functions without a generator
+var defaultGenerator = &NoiseGenerator{classicPerm}
+
+/*
+NewNoiseGenerator makes a noise generator with a permutation table shuffled by a seed
+
+Parameters:
+- seed: the same seed always gives the same noise
+
+Returns:
+- generator: a generator for every noise function of the package
+
+Example usage:
+
+	generator := NewNoiseGenerator(42)
+	sample := generator.Snoise(1.0, 2.0, 3.0)
+*/
+func NewNoiseGenerator(seed int64) *NoiseGenerator {
+	g := &NoiseGenerator{}
+	for i := range g.perm {
+		g.perm[i] = uint8(i)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(g.perm), func(i, j int) {
+		g.perm[i], g.perm[j] = g.perm[j], g.perm[i]
+	})
+
+	return g
+}
+
+// Hashes the corner of a grid cell to a pseudo random byte, the grid repeats every 256 cells
+func (g *NoiseGenerator) hash(i, j, k int) uint8 {
+	return g.perm[(i+int(g.perm[(j+int(g.perm[k&255]))&255]))&255]
 }
 
 func grad3(hash uint8, x, y, z float64) float64 {
@@ -34,7 +77,7 @@ func grad3(hash uint8, x, y, z float64) float64 {
 }
 
 /*
-Snoise samples 3D simplex noise and returns a value between -1.0 and 1.0.
+Snoise samples 3D simplex noise from the default generator and returns a value between -1.0 and 1.0.
 Close samples will return similar values.
 
 Parameters:
@@ -48,9 +91,11 @@ Example usage:
 	sample := Snoise(1.0, 2.0, 3.0)
 */
 func Snoise(x, y, z float32) float32 {
-	// Offset sampling by seed
-	x += seed
+	return defaultGenerator.Snoise(x, y, z)
+}
 
+// Snoise samples 3D simplex noise like the Snoise function, but with the permutation table of the generator
+func (g *NoiseGenerator) Snoise(x, y, z float32) float32 {
 	const F3 = 1.0 / 3.0
 	const G3 = 1.0 / 6.0
 
@@ -106,19 +151,14 @@ func Snoise(x, y, z float32) float32 {
 	y3 := y0 - 1 + 3*G3
 	z3 := z0 - 1 + 3*G3
 
-	// Wrap the integer indices at 256, to avoid indexing perm[] out of bounds
-	i = (i + 256) % 256
-	j = (j + 256) % 256
-	k = (k + 256) % 256
-
 	// Calculate the contribution from the four corners
 	t0 := 0.6 - x0*x0 - y0*y0 - z0*z0
 	if t0 < 0 {
 		n0 = 0
 	} else {
 		t0 *= t0
-		// Calculate the contribution using the grad3 function and permuted indices
-		n0 = t0 * t0 * grad3(perm[(i+int(perm[(j+int(perm[k]))%256]))%256], x0, y0, z0)
+		// Calculate the contribution using the grad3 function and hashed indices
+		n0 = t0 * t0 * grad3(g.hash(i, j, k), x0, y0, z0)
 	}
 
 	t1 := 0.6 - x1*x1 - y1*y1 - z1*z1
@@ -126,8 +166,8 @@ func Snoise(x, y, z float32) float32 {
 		n1 = 0
 	} else {
 		t1 *= t1
-		// Calculate the contribution using the grad3 function and permuted indices
-		n1 = t1 * t1 * grad3(perm[(i+i1+int(perm[(j+j1+int(perm[(k+k1)%256]))%256]))%256], x1, y1, z1)
+		// Calculate the contribution using the grad3 function and hashed indices
+		n1 = t1 * t1 * grad3(g.hash(i+i1, j+j1, k+k1), x1, y1, z1)
 	}
 
 	t2 := 0.6 - x2*x2 - y2*y2 - z2*z2
@@ -135,8 +175,8 @@ func Snoise(x, y, z float32) float32 {
 		n2 = 0
 	} else {
 		t2 *= t2
-		// Calculate the contribution using the grad3 function and permuted indices
-		n2 = t2 * t2 * grad3(perm[(i+i2+int(perm[(j+j2+int(perm[(k+k2)%256]))%256]))%256], x2, y2, z2)
+		// Calculate the contribution using the grad3 function and hashed indices
+		n2 = t2 * t2 * grad3(g.hash(i+i2, j+j2, k+k2), x2, y2, z2)
 	}
 
 	t3 := 0.6 - x3*x3 - y3*y3 - z3*z3
@@ -144,8 +184,8 @@ func Snoise(x, y, z float32) float32 {
 		n3 = 0
 	} else {
 		t3 *= t3
-		// Calculate the contribution using the grad3 function and permuted indices
-		n3 = t3 * t3 * grad3(perm[(i+1+int(perm[(j+1+int(perm[(k+1)%256]))%256]))%256], x3, y3, z3)
+		// Calculate the contribution using the grad3 function and hashed indices
+		n3 = t3 * t3 * grad3(g.hash(i+1, j+1, k+1), x3, y3, z3)
 	}
 
 	// Scale the result to be within -1.0 and 1.0
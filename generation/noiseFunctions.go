@@ -0,0 +1,206 @@
+package generation
+
+import (
+	"fmt"
+	"math"
+)
+
+// Noise is a 3D noise function. Close samples return similar values between -1.0 and 1.0.
+type Noise interface {
+	Sample(x, y, z float32) float32
+}
+
+// NoiseType names one of the noise functions a terrain layer can be built from
+type NoiseType string
+
+const (
+	SimplexNoise NoiseType = "simplex" // smooth blobs, the default
+	PerlinNoise  NoiseType = "perlin"  // like simplex, but aligned to a grid
+	ValueNoise   NoiseType = "value"   // soft, blocky hills
+	WorleyNoise  NoiseType = "worley"  // round cells that peak at their centers, like crater fields
+	CrackNoise   NoiseType = "cracks"  // narrow valleys along the borders of worley cells
+)
+
+// NoiseTypes lists every noise type in the order they are documented
+var NoiseTypes = []NoiseType{SimplexNoise, PerlinNoise, ValueNoise, WorleyNoise, CrackNoise}
+
+// NoiseSettings picks the noise a terrain layer is built from
+type NoiseSettings struct {
+	Type NoiseType // an empty type is simplex noise
+	Warp float32   // how far every sample is moved by domain warping, 0 disables warping
+}
+
+/*
+Noise returns the noise function described by the settings
+
+Parameters:
+- generator: the generator the noise is sampled from, nil for the default generator
+
+Returns:
+- noise: the noise function, wrapped in a DomainWarp if the settings warp the noise
+- err: an error if the type is unknown
+
+Example usage:
+
+	noise, err := NoiseSettings{WorleyNoise, 0.3}.Noise(NewNoiseGenerator(42))
+	height := noise.Sample(0.5, 1.0, 0.0)
+*/
+func (s NoiseSettings) Noise(generator *NoiseGenerator) (Noise, error) {
+	var noise Noise
+	switch s.Type {
+	case "", SimplexNoise:
+		noise = Simplex{generator}
+	case PerlinNoise:
+		noise = Perlin{generator}
+	case ValueNoise:
+		noise = Value{generator}
+	case WorleyNoise:
+		noise = Worley{generator, false}
+	case CrackNoise:
+		noise = Worley{generator, true}
+	default:
+		return nil, fmt.Errorf("unknown noise %q", s.Type)
+	}
+
+	if s.Warp != 0 {
+		noise = DomainWarp{noise, Simplex{generator}, s.Warp}
+	}
+
+	return noise, nil
+}
+
+// Simplex is the simplex noise of Snoise
+type Simplex struct {
+	Generator *NoiseGenerator // nil for the default generator
+}
+
+func (n Simplex) Sample(x, y, z float32) float32 {
+	return orDefault(n.Generator).Snoise(x, y, z)
+}
+
+// Perlin is classic gradient noise, with gradients on the corners of a cube grid
+type Perlin struct {
+	Generator *NoiseGenerator // nil for the default generator
+}
+
+func (n Perlin) Sample(x, y, z float32) float32 {
+	g := orDefault(n.Generator)
+
+	i, j, k, fx, fy, fz := latticeCell(x, y, z)
+	u, v, w := fade(fx), fade(fy), fade(fz)
+
+	// Blend the gradients of the eight corners of the cell
+	corner := func(di, dj, dk int) float64 {
+		return grad3(g.hash(i+di, j+dj, k+dk), fx-float64(di), fy-float64(dj), fz-float64(dk))
+	}
+	x00 := mix(corner(0, 0, 0), corner(1, 0, 0), u)
+	x10 := mix(corner(0, 1, 0), corner(1, 1, 0), u)
+	x01 := mix(corner(0, 0, 1), corner(1, 0, 1), u)
+	x11 := mix(corner(0, 1, 1), corner(1, 1, 1), u)
+	value := mix(mix(x00, x10, v), mix(x01, x11, v), w)
+
+	return float32(math.Max(-1.0, math.Min(1.0, value)))
+}
+
+// Value is value noise, random values on the corners of a cube grid blended together
+type Value struct {
+	Generator *NoiseGenerator // nil for the default generator
+}
+
+func (n Value) Sample(x, y, z float32) float32 {
+	g := orDefault(n.Generator)
+
+	i, j, k, fx, fy, fz := latticeCell(x, y, z)
+	u, v, w := fade(fx), fade(fy), fade(fz)
+
+	// Random values between -1.0 and 1.0 on the eight corners of the cell
+	corner := func(di, dj, dk int) float64 {
+		return float64(g.hash(i+di, j+dj, k+dk))/127.5 - 1.0
+	}
+	x00 := mix(corner(0, 0, 0), corner(1, 0, 0), u)
+	x10 := mix(corner(0, 1, 0), corner(1, 1, 0), u)
+	x01 := mix(corner(0, 0, 1), corner(1, 0, 1), u)
+	x11 := mix(corner(0, 1, 1), corner(1, 1, 1), u)
+
+	return float32(mix(mix(x00, x10, v), mix(x01, x11, v), w))
+}
+
+// Worley is cellular noise from the distances to random feature points, one in every cell of a cube grid
+type Worley struct {
+	Generator *NoiseGenerator // nil for the default generator
+
+	// Cracks returns the difference between the distances to the two closest points instead of the
+	// distance to the closest point, which is lowest along the borders between cells
+	Cracks bool
+}
+
+func (n Worley) Sample(x, y, z float32) float32 {
+	g := orDefault(n.Generator)
+
+	i, j, k, fx, fy, fz := latticeCell(x, y, z)
+
+	// Find the two closest feature points in the neighbouring cells
+	f1, f2 := math.Inf(1), math.Inf(1)
+	for di := -1; di <= 1; di++ {
+		for dj := -1; dj <= 1; dj++ {
+			for dk := -1; dk <= 1; dk++ {
+				h := g.hash(i+di, j+dj, k+dk)
+				px := float64(di) + float64(g.perm[h])/255.0 - fx
+				py := float64(dj) + float64(g.perm[(int(h)+1)&255])/255.0 - fy
+				pz := float64(dk) + float64(g.perm[(int(h)+2)&255])/255.0 - fz
+
+				d := math.Sqrt(px*px + py*py + pz*pz)
+				if d < f1 {
+					f1, f2 = d, f1
+				} else if d < f2 {
+					f2 = d
+				}
+			}
+		}
+	}
+
+	value := 1.0 - 2.0*f1
+	if n.Cracks {
+		value = 2.0*(f2-f1) - 1.0
+	}
+
+	return float32(math.Max(-1.0, math.Min(1.0, value)))
+}
+
+// DomainWarp moves every sample of a noise function by another noise function, which bends and swirls its shapes
+type DomainWarp struct {
+	Base     Noise
+	Warp     Noise
+	Strength float32 // how far samples are moved, relative to the size of the features of the base noise
+}
+
+func (n DomainWarp) Sample(x, y, z float32) float32 {
+	// Sample the warp at offset positions so that the three directions are unrelated
+	wx := n.Warp.Sample(x+31.4, y+15.9, z+26.5)
+	wy := n.Warp.Sample(x-35.8, y+97.9, z-32.3)
+	wz := n.Warp.Sample(x+84.6, y-26.4, z+33.8)
+
+	return n.Base.Sample(x+wx*n.Strength, y+wy*n.Strength, z+wz*n.Strength)
+}
+
+// Returns the integer corner and the fractional position of the grid cell containing a point
+func latticeCell(x, y, z float32) (i, j, k int, fx, fy, fz float64) {
+	floorX, floorY, floorZ := math.Floor(float64(x)), math.Floor(float64(y)), math.Floor(float64(z))
+	return int(floorX), int(floorY), int(floorZ), float64(x) - floorX, float64(y) - floorY, float64(z) - floorZ
+}
+
+func orDefault(generator *NoiseGenerator) *NoiseGenerator {
+	if generator == nil {
+		return defaultGenerator
+	}
+	return generator
+}
+
+// Eases the blending between corners so that the noise has no visible grid lines
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func mix(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
@@ -0,0 +1,112 @@
+package generation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Every noise type, warped and unwarped
+func allNoises(t *testing.T) map[string]Noise {
+	noises := map[string]Noise{}
+	for _, noiseType := range NoiseTypes {
+		for _, warp := range []float32{0, 0.5} {
+			noise, err := NoiseSettings{noiseType, warp}.Noise(nil)
+			if err != nil {
+				t.Fatalf("NoiseSettings{%q, %v}.Noise(nil) returned %v", noiseType, warp, err)
+			}
+			name := string(noiseType)
+			if warp != 0 {
+				name += " warped"
+			}
+			noises[name] = noise
+		}
+	}
+	return noises
+}
+
+func TestNoiseRange(t *testing.T) {
+	for name, noise := range allNoises(t) {
+		rng := rand.New(rand.NewSource(1))
+
+		min, max := float32(1), float32(-1)
+		for i := 0; i < 20000; i++ {
+			p := randomPoint(rng, 1000)
+			v := noise.Sample(p.X(), p.Y(), p.Z())
+			if v < -1 || v > 1 || math.IsNaN(float64(v)) {
+				t.Fatalf("%s noise at %v is %v, want a value between -1 and 1", name, p, v)
+			}
+			min = float32(math.Min(float64(min), float64(v)))
+			max = float32(math.Max(float64(max), float64(v)))
+		}
+
+		// Noise that is constant or always positive would be useless for terrain
+		if min > -0.3 || max < 0.3 {
+			t.Errorf("%s noise only returned values between %v and %v", name, min, max)
+		}
+	}
+}
+
+func TestNoiseContinuity(t *testing.T) {
+	// Every noise is continuous, so a small step can only change the value a little
+	const step = 1e-3
+	const maxChange = 0.05
+
+	for name, noise := range allNoises(t) {
+		rng := rand.New(rand.NewSource(2))
+
+		for i := 0; i < 10000; i++ {
+			p := randomPoint(rng, 100)
+			q := p.Add(randomPoint(rng, 1).Normalize().Mul(step))
+
+			a := noise.Sample(p.X(), p.Y(), p.Z())
+			b := noise.Sample(q.X(), q.Y(), q.Z())
+			if diff := math.Abs(float64(a - b)); diff > maxChange {
+				t.Fatalf("%s noise changed by %v between %v and %v, want at most %v", name, diff, p, q, maxChange)
+			}
+		}
+	}
+}
+
+func TestNoiseSettings(t *testing.T) {
+	// Leaving out the type gives the same noise as before there were noise types
+	noise, err := NoiseSettings{}.Noise(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := noise.(Simplex); !ok {
+		t.Errorf("NoiseSettings{}.Noise(nil) = %T, want Simplex", noise)
+	}
+
+	noise, _ = NoiseSettings{CrackNoise, 1.0}.Noise(nil)
+	if warp, ok := noise.(DomainWarp); !ok || warp.Base != (Worley{nil, true}) {
+		t.Errorf("NoiseSettings{cracks, 1}.Noise(nil) = %#v, want warped cracks", noise)
+	}
+
+	if _, err := (NoiseSettings{"plaid", 0}).Noise(nil); err == nil {
+		t.Error("NoiseSettings{plaid}.Noise(nil) returned no error")
+	}
+}
+
+func TestGenHeightsWithNoiseTypes(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	directions := make([]mgl32.Vec3, 1000)
+	for i := range directions {
+		directions[i] = randomPoint(rng, 1).Normalize()
+	}
+
+	for _, noiseType := range NoiseTypes {
+		shape := DefaultEarth().Shape
+		shape.ContinentNoise = NoiseSettings{noiseType, 0.3}
+		shape.MountainNoise = NoiseSettings{noiseType, 0}
+		shape.MountainMaskNoise = NoiseSettings{noiseType, 0}
+
+		for i, h := range genHeights(t, directions, shape) {
+			if h <= 0 || math.IsNaN(float64(h)) || math.IsInf(float64(h), 0) {
+				t.Fatalf("%s noise gave the height %v at %v", noiseType, h, directions[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package generation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Returns a random point with every coordinate between -scale and scale
+func randomPoint(rng *rand.Rand, scale float32) mgl32.Vec3 {
+	return mgl32.Vec3{
+		(rng.Float32()*2 - 1) * scale,
+		(rng.Float32()*2 - 1) * scale,
+		(rng.Float32()*2 - 1) * scale,
+	}
+}
+
+func TestSnoiseRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100000; i++ {
+		p := randomPoint(rng, 1000)
+		if v := Snoise(p.X(), p.Y(), p.Z()); v < -1 || v > 1 || math.IsNaN(float64(v)) {
+			t.Fatalf("Snoise(%v) = %v, want a value between -1 and 1", p, v)
+		}
+	}
+}
+
+func TestSnoiseNegative(t *testing.T) {
+	// The grid of the noise repeats every 256 cells, also far below zero where the cell indices are negative
+	rng := rand.New(rand.NewSource(5))
+
+	for i := 0; i < 1000; i++ {
+		p := randomPoint(rng, 10)
+		q := p.Sub(mgl32.Vec3{768, 512, 1024})
+		a := Snoise(p.X(), p.Y(), p.Z())
+		b := Snoise(q.X(), q.Y(), q.Z())
+		if diff := math.Abs(float64(a - b)); diff > 1e-3 {
+			t.Fatalf("Snoise(%v) = %v but Snoise(%v) = %v, want the same value", p, a, q, b)
+		}
+	}
+}
+
+func TestSnoiseContinuity(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	// Simplex noise is smooth, so a small step can only change the value a little
+	const step = 1e-3
+	const maxChange = 0.05
+
+	for i := 0; i < 10000; i++ {
+		p := randomPoint(rng, 100)
+		q := p.Add(randomPoint(rng, 1).Normalize().Mul(step))
+
+		a := Snoise(p.X(), p.Y(), p.Z())
+		b := Snoise(q.X(), q.Y(), q.Z())
+		if diff := math.Abs(float64(a - b)); diff > maxChange {
+			t.Fatalf("Snoise changed by %v between %v and %v, want at most %v", diff, p, q, maxChange)
+		}
+	}
+}
+
+func TestSnoiseDeterministic(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 1000; i++ {
+		p := randomPoint(rng, 100)
+		if a, b := Snoise(p.X(), p.Y(), p.Z()), Snoise(p.X(), p.Y(), p.Z()); a != b {
+			t.Fatalf("Snoise(%v) returned %v and then %v", p, a, b)
+		}
+	}
+}
+
+func TestSnoiseVaries(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+
+	// Noise that is constant or always positive would be useless for terrain
+	min, max := float32(1), float32(-1)
+	for i := 0; i < 10000; i++ {
+		p := randomPoint(rng, 100)
+		v := Snoise(p.X(), p.Y(), p.Z())
+		min = float32(math.Min(float64(min), float64(v)))
+		max = float32(math.Max(float64(max), float64(v)))
+	}
+
+	if min > -0.5 || max < 0.5 {
+		t.Errorf("Snoise only returned values between %v and %v", min, max)
+	}
+}
+
+func TestDetailedNoiseRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+
+	// Five octaves with halving amplitude add up to at most 1.9375 times the amplitude
+	const amplitude = 2.5
+	const bound = amplitude * 1.9375
+
+	for i := 0; i < 10000; i++ {
+		p := randomPoint(rng, 1).Normalize()
+		if v := DetailedNoise(p, amplitude, 3.0); math.Abs(float64(v)) > bound {
+			t.Fatalf("DetailedNoise(%v) = %v, want a value between %v and %v", p, v, -bound, bound)
+		}
+	}
+}
+
+func TestRidgeNoiseRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+
+	const amplitude = 2.0
+
+	for i := 0; i < 10000; i++ {
+		p := randomPoint(rng, 1).Normalize()
+		v := RidgeNoise(p, amplitude, 3.0)
+		if v > amplitude*0.5 || v < amplitude*(0.5-1.9375) {
+			t.Fatalf("RidgeNoise(%v) = %v, want a value between %v and %v", p, v, amplitude*(0.5-1.9375), amplitude*0.5)
+		}
+	}
+}
+
+func TestNoiseGeneratorPermutation(t *testing.T) {
+	for _, seed := range []int64{0, 1, -7, 1 << 40} {
+		g := NewNoiseGenerator(seed)
+
+		// Every byte appears exactly once, or some gradients would never be picked
+		seen := [256]bool{}
+		for _, p := range g.perm {
+			if seen[p] {
+				t.Fatalf("seed %d: %d appears twice in the permutation table", seed, p)
+			}
+			seen[p] = true
+		}
+	}
+}
+
+func TestNoiseGeneratorSeeds(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	a, b, c := NewNoiseGenerator(1), NewNoiseGenerator(1), NewNoiseGenerator(2)
+
+	same := 0
+	for i := 0; i < 1000; i++ {
+		p := randomPoint(rng, 100)
+		va, vb, vc := a.Snoise(p.X(), p.Y(), p.Z()), b.Snoise(p.X(), p.Y(), p.Z()), c.Snoise(p.X(), p.Y(), p.Z())
+
+		if va != vb {
+			t.Fatalf("two generators with seed 1 returned %v and %v at %v", va, vb, p)
+		}
+		if va == vc {
+			same++
+		}
+	}
+
+	// Only the rare samples on a lattice point can be equal
+	if same > 10 {
+		t.Errorf("seeds 1 and 2 returned the same noise at %d of 1000 points", same)
+	}
+}
+
+func TestNoiseGeneratorConcurrent(t *testing.T) {
+	g := NewNoiseGenerator(3)
+	want := g.Snoise(1.5, 2.5, 3.5)
+
+	// A generator is never changed by sampling, so goroutines can share it
+	results := make(chan float32, 8)
+	for i := 0; i < cap(results); i++ {
+		go func() {
+			var v float32
+			for j := 0; j < 1000; j++ {
+				v = g.Snoise(1.5, 2.5, 3.5)
+			}
+			results <- v
+		}()
+	}
+	for i := 0; i < cap(results); i++ {
+		if v := <-results; v != want {
+			t.Fatalf("a goroutine sampled %v, want %v", v, want)
+		}
+	}
+}
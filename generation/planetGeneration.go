@@ -0,0 +1,397 @@
+// Package generation generates the terrain and meshes of planets on the CPU, without any OpenGL dependency.
+package generation
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// VertexStride is the number of floats in every vertex from GenPlanet, a position(3 floats), a normal(3 floats),
+// a Biome(1 float) and how much of it is covered in ice(1 float)
+const VertexStride = 8
+
+/*
+GenPlanet generates the vertices and indices of a planet from a described planet shape
+
+Parameters:
+- shape: the planet shape struct containing a recipe for the planets shape
+
+Returns:
+- vertices: the vertices of the planet, as a float32 array
+- indices: the indices of the vertices that form the triangles of the planet
+- err: an error if the terrain graph of the shape is invalid
+
+Example usage:
+
+	earthSettings := DefaultEarth()
+	vertices, indices, err := GenPlanet(earthSettings.Shape)
+*/
+func GenPlanet(shape PlanetShape) ([]float32, []uint32, error) {
+	vertices, indices, _, err := GenPlanetWithWater(shape)
+	return vertices, indices, err
+}
+
+/*
+GenPlanetWithWater is like GenPlanet but also returns the rivers and lakes of the planet
+
+Parameters:
+- shape: the planet shape struct containing a recipe for the planets shape
+
+Returns:
+- vertices: the vertices of the planet, as a float32 array
+- indices: the indices of the vertices that form the triangles of the planet
+- water: the rivers and lakes of the planet, empty if the shape has no rivers
+- err: an error if the terrain graph of the shape is invalid
+
+Example usage:
+
+	settings := DefaultEarth()
+	settings.Shape.RiverMinArea = 0.002
+	vertices, indices, water, err := GenPlanetWithWater(settings.Shape)
+*/
+func GenPlanetWithWater(shape PlanetShape) ([]float32, []uint32, Hydrology, error) {
+	mesh, err := GenPlanetMesh(context.Background(), shape, nil)
+	return mesh.Vertices, mesh.Indices, mesh.Water, err
+}
+
+// PlanetMesh is everything GenPlanetWithWater generates for a planet
+type PlanetMesh struct {
+	Vertices []float32 // the vertices of the planet, VertexStride floats each
+	Indices  []uint32  // the indices of the vertices that form the triangles of the planet
+	Water    Hydrology // the rivers and lakes of the planet, empty if the shape has no rivers
+}
+
+// Progress is told how much of a planet has been generated, from 0 to 1
+type Progress func(fraction float32)
+
+// How many steps GenPlanetMesh reports progress after
+const planetSteps = 7
+
+/*
+GenPlanetMesh is like GenPlanetWithWater but stops when the context is cancelled, and reports its progress after every step.
+Every step runs to its end before the context is checked, so a cancelled planet can take a moment to stop.
+
+Parameters:
+- ctx: the context that cancels the generation
+- shape: the planet shape struct containing a recipe for the planets shape
+- progress: called after every step with how much of the planet has been generated, may be nil
+
+Returns:
+- mesh: the vertices, indices, rivers and lakes of the planet
+- err: an error if the terrain graph of the shape is invalid, or the error of the context if it was cancelled
+
+Example usage:
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	mesh, err := GenPlanetMesh(ctx, DefaultEarth().Shape, func(fraction float32) {
+		fmt.Printf("%.0f%%\n", fraction*100)
+	})
+*/
+func GenPlanetMesh(ctx context.Context, shape PlanetShape, progress Progress) (PlanetMesh, error) {
+	step := 0
+	done := func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		step++
+		if progress != nil {
+			progress(float32(step) / planetSteps)
+		}
+		return nil
+	}
+
+	// A job that was cancelled while it waited is not started
+	if err := ctx.Err(); err != nil {
+		return PlanetMesh{}, err
+	}
+
+	// An invalid terrain graph fails the planet before any work is done
+	terrain, err := CompileTerrain(shape)
+	if err != nil {
+		return PlanetMesh{}, err
+	}
+
+	// Scale resolution by radius to give larger planets more detail
+	scaledRes := uint32(float32(shape.Res) * shape.Radius)
+	points, indices := genOctahedron(scaledRes)
+
+	normalizePointDistances(points)
+	if err := done(); err != nil {
+		return PlanetMesh{}, err
+	}
+
+	// Skip fancy generation if it will result in a sphere anyways
+	if shape.Amplitude != 0.0 {
+		terrain.displace(points)
+	}
+	if err := done(); err != nil {
+		return PlanetMesh{}, err
+	}
+
+	// Weather the terrain and let water flow over it, if the shape asks for it
+	ErodeTerrain(points, indices, shape)
+	if err := done(); err != nil {
+		return PlanetMesh{}, err
+	}
+	water := GenHydrology(points, indices, shape)
+	if err := done(); err != nil {
+		return PlanetMesh{}, err
+	}
+	climate := GenClimate(points, indices, shape)
+	if err := done(); err != nil {
+		return PlanetMesh{}, err
+	}
+
+	// Cover the poles and mountain tops in ice, sea ice floats on the surface
+	ice := iceOfPoints(points, shape)
+	if ice != nil {
+		floatSeaIce(points, ice)
+	}
+
+	normals := calculateVertexNormals(points, indices)
+	if err := done(); err != nil {
+		return PlanetMesh{}, err
+	}
+
+	// Add points, normals, biomes and ice together as vertices in float32 array
+	vertices := make([]float32, 0, len(points)*VertexStride)
+	for i := 0; i < len(points); i++ {
+		biome := NoBiome
+		if climate.Biomes != nil {
+			biome = climate.Biomes[i]
+		}
+		covered := float32(0.0)
+		if ice != nil {
+			covered = ice[i]
+		}
+
+		vertices = append(vertices,
+			points[i][0],
+			points[i][1],
+			points[i][2],
+			normals[i][0],
+			normals[i][1],
+			normals[i][2],
+			float32(biome),
+			covered)
+	}
+	if err := done(); err != nil {
+		return PlanetMesh{}, err
+	}
+
+	return PlanetMesh{vertices, indices, water}, nil
+}
+
+/*
+GenPlaceholder generates a smooth sphere with few vertices, to draw while the real planet is generated
+
+Parameters:
+- resolution: how many times the sides of the octahedron the sphere is made from are divided
+
+Returns:
+- vertices: the vertices of the sphere at sea level, laid out like the vertices from GenPlanet
+- indices: the indices of the vertices that form the triangles of the sphere
+
+Example usage:
+
+	vertices, indices := GenPlaceholder(8)
+*/
+func GenPlaceholder(resolution uint32) ([]float32, []uint32) {
+	points, indices := genOctahedron(resolution)
+	normalizePointDistances(points)
+
+	// The normal of every point on a sphere is the point itself
+	vertices := make([]float32, 0, len(points)*VertexStride)
+	for _, point := range points {
+		vertices = append(vertices, point[0], point[1], point[2], point[0], point[1], point[2], float32(NoBiome), 0)
+	}
+
+	return vertices, indices
+}
+
+// Generates points and indices of an octahedron with specified resolution.
+func genOctahedron(res uint32) ([]mgl32.Vec3, []uint32) {
+	// Points and indices of octahedron:
+	corners := []mgl32.Vec3{
+		{0.0, 1.0, 0.0},  // 0
+		{1.0, 0.0, 0.0},  // 1
+		{0.0, 0.0, 1.0},  // 2
+		{-1.0, 0.0, 0.0}, // 3
+		{0.0, 0.0, -1.0}, // 4
+		{0.0, -1.0, 0.0}, // 5
+	}
+
+	faces := []float32{
+		// Top faces:
+		0, 1, 2,
+		0, 2, 3,
+		0, 3, 4,
+		0, 4, 1,
+		// Bottom faces:
+		5, 2, 1,
+		5, 3, 2,
+		5, 4, 3,
+		5, 1, 4,
+	}
+
+	points := []mgl32.Vec3{}
+	indices := []uint32{}
+
+	index := uint32(0)
+
+	// Generate 8 divided triangle faces
+	for i := 0; i < 8; i++ {
+		triPoints, triInds := genDividedTriangle(
+			corners[uint32(faces[i*3])],
+			corners[uint32(faces[i*3+1])],
+			corners[uint32(faces[i*3+2])],
+			res,
+			&index)
+
+		points = append(points, triPoints...)
+		indices = append(indices, triInds...)
+	}
+
+	// Merge octahedron faces at seams before returning
+	points = mergeDuplicateVertices(points, indices)
+
+	return points, indices
+}
+
+/*
+points:
+
+i
+0		   B
+|		  / \
+1		 o---o
+|		/ \ / \
+2	  BA-BABC--BC
+|     / \ / \ / \
+3    o---o---o---o
+|	/ \ / \ / \ / \
+4  A---o---o---o---C
+|
+	0___1___2___3___4   k
+
+indices:
+
+i
+0		   0
+|		  / \
+1		 1---2
+|		/ \ / \
+2	   3---4---5
+|     / \ / \ / \
+3    6---7---8---9
+|	/ \ / \ / \ / \
+4  10--11--12--13--14
+|
+	0___1___2___3___4   k
+
+*/
+
+// Generates the points and indices of triangle ABC with "res" number of subdivisions along edge.
+func genDividedTriangle(A, B, C mgl32.Vec3, res uint32, index *uint32) ([]mgl32.Vec3, []uint32) {
+	points := []mgl32.Vec3{}
+	indices := []uint32{}
+
+	for i := uint32(0); i <= res; i++ {
+		for k := uint32(0); k <= i; k++ {
+			// BABC is the point k steps from BA towards BC, where BA and BC are i steps from B towards C and A.
+			// It is weighted from the corners with whole numbers so that points shared by two faces are exactly equal.
+			BABC := A.Mul(float32(k)).Add(B.Mul(float32(res - i))).Add(C.Mul(float32(i - k))).Mul(1.0 / float32(res))
+
+			// Add point
+			points = append(points, BABC)
+
+			// Add the two new triangles formed by adding the point
+			if i < res {
+				indices = append(indices, *index, *index+i+2, *index+i+1)
+				if k < i {
+					indices = append(indices, *index, *index+1, *index+i+2)
+				}
+			}
+
+			*index++
+		}
+	}
+
+	return points, indices
+}
+
+// Merges duplicate vertices, updates indices accordingly and returns the remaining vertices.
+func mergeDuplicateVertices(vertices []mgl32.Vec3, indices []uint32) []mgl32.Vec3 {
+	uniqueVertices := make(map[mgl32.Vec3]uint32)
+	mergedIndices := make([]uint32, len(indices))
+
+	for i := 0; i < len(indices); i++ {
+		vertexPos := roundVec3(vertices[indices[i]])
+
+		// Check if vertex is already in uniqueVertices
+		mergedIndex, inMap := uniqueVertices[vertexPos]
+
+		if !inMap {
+			// Vertex is not in map, add it to uniqueVertices
+			mergedIndex = uint32(len(uniqueVertices))
+			uniqueVertices[vertexPos] = mergedIndex
+		}
+
+		// Update index in the mergedIndices array
+		mergedIndices[i] = mergedIndex
+	}
+
+	// Modify vertices to remove duplicates
+	newVertices := make([]mgl32.Vec3, len(uniqueVertices))
+	for vertex, index := range uniqueVertices {
+		newVertices[index] = vertex
+	}
+
+	copy(vertices, newVertices)
+	copy(indices, mergedIndices)
+
+	return vertices[:len(newVertices)]
+}
+
+func roundVec3(vec mgl32.Vec3) mgl32.Vec3 {
+	return mgl32.Vec3{
+		float32(math.Round(float64(vec[0])*1e7) / 1e7),
+		float32(math.Round(float64(vec[1])*1e7) / 1e7),
+		float32(math.Round(float64(vec[2])*1e7) / 1e7),
+	}
+}
+
+// Set distance of every point to radius from origin.
+func normalizePointDistances(points []mgl32.Vec3) {
+	for i := 0; i < len(points); i++ {
+		points[i] = points[i].Normalize()
+	}
+}
+
+// Calculate an array of normal vectors pointing straight out from every corner of shape.
+func calculateVertexNormals(points []mgl32.Vec3, indices []uint32) []mgl32.Vec3 {
+	normals := make([]mgl32.Vec3, len(points))
+
+	// Calcualte surface normal of each face
+	for i := 0; i < len(indices); i += 3 {
+		v1 := points[indices[i]]
+		v2 := points[indices[i+1]]
+		v3 := points[indices[i+2]]
+
+		// Calculate normal using cross product
+		normal := v2.Sub(v1).Cross(v3.Sub(v1))
+
+		normals[indices[i]] = normals[indices[i]].Add(normal)
+		normals[indices[i+1]] = normals[indices[i+1]].Add(normal)
+		normals[indices[i+2]] = normals[indices[i+2]].Add(normal)
+	}
+
+	for i := 0; i < len(normals); i++ {
+		normals[i] = normals[i].Normalize()
+	}
+
+	return normals
+}
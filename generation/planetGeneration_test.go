@@ -0,0 +1,145 @@
+package generation
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestGenOctahedronCounts(t *testing.T) {
+	for _, res := range []uint32{1, 2, 3, 5, 10, 32} {
+		points, indices := genOctahedron(res)
+
+		// Every face of the octahedron is split into res*res triangles, and the closed
+		// surface has V - E + F = 2 with E = 3F/2
+		wantTriangles := 8 * int(res*res)
+		wantPoints := 4*int(res*res) + 2
+
+		if len(indices)/3 != wantTriangles {
+			t.Errorf("res %d: %d triangles, want %d", res, len(indices)/3, wantTriangles)
+		}
+		if len(points) != wantPoints {
+			t.Errorf("res %d: %d points, want %d", res, len(points), wantPoints)
+		}
+	}
+}
+
+func TestGenDividedTriangleSeams(t *testing.T) {
+	// Two faces of the octahedron that share the edge from the top corner to +Z. The points of the edge have to be
+	// exactly equal in both, or they are not merged and the planet gets cracks along its seams.
+	top, right, front, left := mgl32.Vec3{0, 1, 0}, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 0, 1}, mgl32.Vec3{-1, 0, 0}
+
+	for _, res := range []uint32{3, 7, 10, 33} {
+		index := uint32(0)
+		a, _ := genDividedTriangle(top, right, front, res, &index)
+		b, _ := genDividedTriangle(top, front, left, res, &index)
+
+		shared := map[mgl32.Vec3]bool{}
+		for _, p := range a {
+			if p.X() == 0 {
+				shared[p] = true
+			}
+		}
+		found := 0
+		for _, p := range b {
+			if p.X() == 0 {
+				if !shared[p] {
+					t.Fatalf("res %d: point %v of the second face is not a point of the first", res, p)
+				}
+				found++
+			}
+		}
+		if len(shared) != int(res)+1 || found != int(res)+1 {
+			t.Errorf("res %d: faces have %d and %d points on their shared edge, want %d", res, len(shared), found, res+1)
+		}
+	}
+}
+
+func TestGenOctahedronManifold(t *testing.T) {
+	for _, res := range []uint32{1, 2, 7, 16} {
+		points, indices := genOctahedron(res)
+		normalizePointDistances(points)
+
+		used := make([]bool, len(points))
+		// Every directed edge of a closed, consistently wound surface is used by exactly one triangle,
+		// and its reverse by exactly one other
+		edges := map[[2]uint32]int{}
+
+		for i := 0; i < len(indices); i += 3 {
+			tri := [3]uint32{indices[i], indices[i+1], indices[i+2]}
+
+			for k, index := range tri {
+				if int(index) >= len(points) {
+					t.Fatalf("res %d: index %d is out of range of %d points", res, index, len(points))
+				}
+				used[index] = true
+				edges[[2]uint32{index, tri[(k+1)%3]}]++
+			}
+
+			if tri[0] == tri[1] || tri[1] == tri[2] || tri[2] == tri[0] {
+				t.Fatalf("res %d: triangle %v is degenerate", res, tri)
+			}
+
+			// Triangles are wound counter-clockwise as seen from outside
+			a, b, c := points[tri[0]], points[tri[1]], points[tri[2]]
+			normal := b.Sub(a).Cross(c.Sub(a))
+			if normal.Dot(a.Add(b).Add(c)) <= 0 {
+				t.Fatalf("res %d: triangle %v faces inwards", res, tri)
+			}
+		}
+
+		for edge, count := range edges {
+			if count != 1 {
+				t.Fatalf("res %d: edge %v is used by %d triangles, want 1", res, edge, count)
+			}
+			if edges[[2]uint32{edge[1], edge[0]}] != 1 {
+				t.Fatalf("res %d: edge %v has no opposite edge, the surface is not closed", res, edge)
+			}
+		}
+
+		for i := range used {
+			if !used[i] {
+				t.Fatalf("res %d: point %d is not used by any triangle", res, i)
+			}
+		}
+	}
+}
+
+// Generates the vertices and indices of a shape whose terrain graph is valid
+func genPlanet(t *testing.T, shape PlanetShape) ([]float32, []uint32) {
+	t.Helper()
+
+	vertices, indices, err := GenPlanet(shape)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vertices, indices
+}
+
+func TestGenPlanetVertices(t *testing.T) {
+	shape := DefaultMoon().Shape
+	shape.Res = 20
+
+	vertices, indices := genPlanet(t, shape)
+
+	if len(vertices)%VertexStride != 0 {
+		t.Fatalf("%d floats is not a whole number of vertices", len(vertices))
+	}
+	numVertices := len(vertices) / VertexStride
+	for _, index := range indices {
+		if int(index) >= numVertices {
+			t.Fatalf("index %d is out of range of %d vertices", index, numVertices)
+		}
+	}
+}
+
+func BenchmarkGenPlanet(b *testing.B) {
+	shape := DefaultEarth().Shape
+	shape.Res = 100
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := GenPlanet(shape); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
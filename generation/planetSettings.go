@@ -0,0 +1,499 @@
+package generation
+
+import (
+	"math/rand"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type PlanetSettings struct {
+	Shape  PlanetShape
+	Colors PlanetColors
+
+	HasAtmosphere bool
+	HasOcean      bool
+
+	TexturePath   string
+	NormalMapPath string
+	ShaderPath    string
+
+	TextureScale   float32
+	NormalMapScale float32
+}
+
+type PlanetShape struct {
+	Seed      int64
+	Radius    float32
+	Res       uint32
+	Amplitude float32
+	Frequency float32
+
+	OceanDepth      float32
+	OceanFloorDepth float32
+	OceanSmoothness float32
+
+	ContinentAmplitude float32
+	ContinentFrequency float32
+	ContinentNoise     NoiseSettings
+	ContinentFractal   FractalSettings
+
+	PlateCount          uint32  // tectonic plates that the continents are built from, 0 builds them from noise
+	PlateLandFraction   float32 // part of the plates that are continental
+	PlateMountainHeight float32 // height of the mountains where plates push into each other
+	PlateMountainWidth  float32 // how far from the boundary the mountains and rifts reach
+	PlateRiftDepth      float32 // depth of the rifts and trenches at the boundaries
+	PlateRoughness      float32 // how far the boundaries are bent by the continent noise
+
+	MountainAmplitude  float32
+	MountainFrequency  float32
+	MountainSmoothness float32
+	MountainNoise      NoiseSettings
+	MountainFractal    FractalSettings
+
+	MountainMaskAmplitude  float32
+	MountainMaskSmoothness float32
+	MountainMaskOffset     float32
+	MountainMaskNoise      NoiseSettings
+	MountainMaskFractal    FractalSettings
+
+	NumCraters         uint32
+	CraterRimWidth     float32
+	CraterRimSteepness float32
+	CraterSmoothness   float32
+	CraterFloorHeight  float32
+
+	CraterMinRadius     float32
+	CraterMaxRadius     float32
+	CraterSizeExponent  float32
+	CraterPeakHeight    float32
+	CraterPeakMinRadius float32
+	CraterRimNoise      float32
+	CraterEjectaHeight  float32
+	CraterEjectaWidth   float32
+	CraterSpacing       float32
+
+	ErosionIterations  uint32  // rounds of rain, every round drops a drop of rain on every vertex
+	ErosionRain        float32 // water in every drop, drops with more water flow further and carry more
+	ErosionCapacity    float32 // how much sediment a drop can carry for its water and the height it falls
+	ErosionEvaporation float32 // part of the water of a drop that evaporates at every vertex
+	ErosionRate        float32 // part of the free capacity of a drop that it fills with sediment at every vertex
+	ErosionDeposition  float32 // part of the sediment over the capacity of a drop that it leaves at every vertex
+	ThermalIterations  uint32  // rounds of steep slopes crumbling
+	ThermalTalus       float32 // the steepest slope that does not crumble
+	ThermalRate        float32 // how much of a too steep slope crumbles every round
+
+	RiverMinArea float32 // part of the planet surface that has to drain through a vertex for a river to flow there, 0 turns rivers and lakes off
+	RiverDepth   float32 // how deep the largest rivers are carved
+	RiverWidth   float32 // how wide the smallest rivers are drawn
+
+	HasClimate         bool    // sort every vertex into a biome, false colors the planet by height and slope alone
+	AxialTilt          float32 // degrees the axis is tilted, which moves warmth from the equator towards the poles
+	EquatorTemperature float32 // °C at sea level on the equator of a planet without tilt
+	PoleTemperature    float32 // °C at sea level on the poles of a planet without tilt
+	LapseRate          float32 // °C colder for every planet radius above sea level
+	Rainfall           float32 // cm of precipitation a year where wet winds come in from the sea
+	MoistureReach      float32 // how far inland winds carry moisture, in planet radii
+	RainShadow         float32 // how much moisture winds lose for every planet radius they climb
+
+	HasIce         bool    // cover the poles and mountain tops in ice and snow
+	IceCapLatitude float32 // degrees from the equator where the ice caps begin at sea level
+	SnowlineHeight float32 // height above sea level where snow begins on the equator, 0 keeps snow off the mountains
+	IceRoughness   float32 // degrees of latitude the edges of the ice are moved by noise
+
+	LODLevels uint32 // how many times the surface can be split into smaller chunks close to the camera, 0 draws one mesh from GenPlanet
+
+	Terrain []TerrainNode // nil builds the terrain from the settings above with DefaultTerrain
+}
+
+type PlanetColors struct {
+	ShoreColLow  mgl32.Vec3
+	ShoreColHigh mgl32.Vec3
+	FlatColLow   mgl32.Vec3
+	FlatColHigh  mgl32.Vec3
+	SteepColLow  mgl32.Vec3
+	SteepColHigh mgl32.Vec3
+	WaterCol     mgl32.Vec3
+	IceCol       mgl32.Vec3
+}
+
+// Presets that can be picked by name from the command line and from settings files
+var presets = map[string]func() PlanetSettings{
+	"earth": DefaultEarth,
+	"moon":  DefaultMoon,
+	"sun":   DefaultSun,
+}
+
+func DefaultEarth() PlanetSettings {
+	return PlanetSettings{
+		PlanetShape{
+			// General:
+			1,   // seed
+			1.0, // radius
+			200, // resolution
+			0.5, // amplitude
+			1.0, // frequency
+
+			// Ocean:
+			7.0, // depth
+			0.4, // floor depth
+			0.8, // smoothness
+
+			// Continent:
+			0.15, // amplitude
+			1.0,  // frequency
+			NoiseSettings{SimplexNoise, 0.0},
+			FractalSettings{5, 2.0, 0.5, false},
+
+			// Plates:
+			0,    // count
+			0.35, // land fraction
+			1.5,  // mountain height
+			0.08, // mountain width
+			0.8,  // rift depth
+			0.15, // roughness
+
+			// Mountain:
+			0.2,  // amplitude
+			0.75, // frequency
+			0.5,  // smoothness
+			NoiseSettings{SimplexNoise, 0.0},
+			FractalSettings{5, 2.0, 0.5, false},
+
+			// Mountain Mask:
+			1.1,  // amplitude
+			0.4,  // smoothness
+			-0.5, // offset
+			NoiseSettings{SimplexNoise, 0.0},
+			FractalSettings{5, 2.0, 0.5, false},
+
+			// Crater:
+			0,    // count
+			0.7,  // rim width
+			0.4,  // rim steepness
+			0.3,  // smoothness
+			-0.3, // floor height
+			0.0,  // min radius
+			0.25, // max radius
+			0.5,  // size exponent
+			0.0,  // peak height
+			0.0,  // peak min radius
+			0.0,  // rim noise
+			0.0,  // ejecta height
+			0.0,  // ejecta width
+			0.0,  // spacing
+
+			// Erosion:
+			0,    // iterations
+			1.0,  // rain
+			4.0,  // capacity
+			0.05, // evaporation
+			0.1,  // rate
+			0.3,  // deposition
+			0,    // thermal iterations
+			0.6,  // talus
+			0.5,  // thermal rate
+
+			// Rivers:
+			0.0,   // min area
+			0.004, // depth
+			0.003, // width
+
+			// Climate:
+			false, // has climate
+			23.44, // axial tilt
+			30.0,  // equator temperature
+			-30.0, // pole temperature
+			300.0, // lapse rate
+			300.0, // rainfall
+			0.6,   // moisture reach
+			40.0,  // rain shadow
+
+			// Ice:
+			true, // has ice
+			72.0, // ice cap latitude
+			0.15, // snowline height
+			8.0,  // ice roughness
+
+			// Level of detail:
+			0, // lod levels
+
+			// Terrain:
+			nil, // built from the settings above
+		},
+
+		PlanetColors{
+			mgl32.Vec3{0.98, 0.90, 0.62},
+			mgl32.Vec3{0.95, 0.79, 0.41},
+			mgl32.Vec3{0.49, 0.62, 0.00},
+			mgl32.Vec3{0.26, 0.41, 0.00},
+			mgl32.Vec3{0.42, 0.32, 0.24},
+			mgl32.Vec3{0.90, 0.90, 0.95},
+			mgl32.Vec3{0.50, 0.50, 0.90},
+			mgl32.Vec3{0.92, 0.95, 1.00},
+		},
+
+		true, // has atmosphere
+		true, // has oceans
+
+		"spots.png",           // texture
+		"normalmap_rocky.png", // normal map
+		"planet.shader",       // shader
+
+		1.0, // texture scale
+		3.0, // normal map scale
+	}
+}
+
+func DefaultMoon() PlanetSettings {
+	return PlanetSettings{
+		PlanetShape{
+			// General:
+			2,   // seed
+			1.0, // radius
+			100, // resolution
+			1.0, // amplitude
+			1.0, // frequency
+
+			// Ocean:
+			1.0,  // depth
+			10.0, // floor depth
+			0.0,  // smoothness
+
+			// Continent:
+			0.15, // amplitude
+			1.0,  // frequency
+			NoiseSettings{SimplexNoise, 0.0},
+			FractalSettings{5, 2.0, 0.5, false},
+
+			// Plates:
+			0,    // count
+			0.35, // land fraction
+			1.5,  // mountain height
+			0.08, // mountain width
+			0.8,  // rift depth
+			0.15, // roughness
+
+			// Mountain:
+			1.1, // amplitude
+			0.1, // frequency
+			0.1, // smoothness
+			NoiseSettings{SimplexNoise, 0.0},
+			FractalSettings{5, 2.0, 0.5, false},
+
+			// Mountain Mask:
+			1.1,  // amplitude
+			0.1,  // smoothness
+			-0.1, // offset
+			NoiseSettings{SimplexNoise, 0.0},
+			FractalSettings{5, 2.0, 0.5, false},
+
+			// Crater:
+			150,  // count
+			0.7,  // rim width
+			0.4,  // rim steepness
+			0.3,  // smoothness
+			-0.3, // floor height
+			0.02, // min radius
+			0.3,  // max radius
+			1.8,  // size exponent
+			0.25, // peak height
+			0.1,  // peak min radius
+			0.08, // rim noise
+			0.12, // ejecta height
+			1.2,  // ejecta width
+			0.8,  // spacing
+
+			// Erosion:
+			0,    // iterations
+			1.0,  // rain
+			4.0,  // capacity
+			0.05, // evaporation
+			0.1,  // rate
+			0.3,  // deposition
+			0,    // thermal iterations
+			0.6,  // talus
+			0.5,  // thermal rate
+
+			// Rivers:
+			0.0,   // min area
+			0.004, // depth
+			0.003, // width
+
+			// Climate:
+			false, // has climate
+			23.44, // axial tilt
+			30.0,  // equator temperature
+			-30.0, // pole temperature
+			300.0, // lapse rate
+			300.0, // rainfall
+			0.6,   // moisture reach
+			40.0,  // rain shadow
+
+			// Ice:
+			false, // has ice
+			72.0,  // ice cap latitude
+			0.15,  // snowline height
+			8.0,   // ice roughness
+
+			// Level of detail:
+			0, // lod levels
+
+			// Terrain:
+			nil, // built from the settings above
+		},
+
+		PlanetColors{
+			mgl32.Vec3{0.60, 0.60, 0.60},
+			mgl32.Vec3{0.60, 0.60, 0.60},
+			mgl32.Vec3{0.60, 0.60, 0.60},
+			mgl32.Vec3{0.60, 0.60, 0.60},
+			mgl32.Vec3{0.60, 0.60, 0.60},
+			mgl32.Vec3{0.60, 0.60, 0.60},
+			mgl32.Vec3{0.00, 0.00, 0.00},
+			mgl32.Vec3{0.90, 0.90, 0.90},
+		},
+
+		false, // has atmosphere
+		false, // has oceans
+
+		"spots.png",           // texture
+		"normalmap_rocky.png", // normal map
+		"planet.shader",       // shader
+
+		3.0, // texture scale
+		0.5, // normal map scale
+	}
+}
+
+func DefaultSun() PlanetSettings {
+	return PlanetSettings{
+		PlanetShape{
+			// General:
+			3,    // seed
+			10.0, // radius
+			50,   // resolution
+			0.0,  // amplitude
+			0.0,  // frequency
+
+			// Ocean:
+			1.0, // depth
+			0.0, // floor depth
+			0.0, // smoothness
+
+			// Continent:
+			0.0, // amplitude
+			0.0, // frequency
+			NoiseSettings{SimplexNoise, 0.0},
+			FractalSettings{5, 2.0, 0.5, false},
+
+			// Plates:
+			0,    // count
+			0.35, // land fraction
+			1.5,  // mountain height
+			0.08, // mountain width
+			0.8,  // rift depth
+			0.15, // roughness
+
+			// Mountain:
+			0.0, // amplitude
+			0.0, // frequency
+			0.0, // smoothness
+			NoiseSettings{SimplexNoise, 0.0},
+			FractalSettings{5, 2.0, 0.5, false},
+
+			// Mountain Mask:
+			0.0, // amplitude
+			0.0, // smoothness
+			0.0, // offset
+			NoiseSettings{SimplexNoise, 0.0},
+			FractalSettings{5, 2.0, 0.5, false},
+
+			// Crater:
+			0,   // count
+			0.0, // rim width
+			0.0, // rim steepness
+			0.0, // smoothness
+			0.0, // floor height
+			0.0, // min radius
+			0.0, // max radius
+			0.0, // size exponent
+			0.0, // peak height
+			0.0, // peak min radius
+			0.0, // rim noise
+			0.0, // ejecta height
+			0.0, // ejecta width
+			0.0, // spacing
+
+			// Erosion:
+			0,    // iterations
+			1.0,  // rain
+			4.0,  // capacity
+			0.05, // evaporation
+			0.1,  // rate
+			0.3,  // deposition
+			0,    // thermal iterations
+			0.6,  // talus
+			0.5,  // thermal rate
+
+			// Rivers:
+			0.0,   // min area
+			0.004, // depth
+			0.003, // width
+
+			// Climate:
+			false, // has climate
+			23.44, // axial tilt
+			30.0,  // equator temperature
+			-30.0, // pole temperature
+			300.0, // lapse rate
+			300.0, // rainfall
+			0.6,   // moisture reach
+			40.0,  // rain shadow
+
+			// Ice:
+			false, // has ice
+			72.0,  // ice cap latitude
+			0.15,  // snowline height
+			8.0,   // ice roughness
+
+			// Level of detail:
+			0, // lod levels
+
+			// Terrain:
+			nil, // built from the settings above
+		},
+
+		PlanetColors{},
+
+		true,  // has atmosphere
+		false, // has oceans
+
+		"sun.png",    // texture
+		"sun.png",    // normal map
+		"sun.shader", // shader
+
+		1.2, // texture scale
+		0.0, // normal map scale
+	}
+}
+
+func RandomColors() PlanetColors {
+	// Generate random base colors
+	shoreCol := mgl32.Vec3{rand.Float32(), rand.Float32(), rand.Float32()}
+	flatCol := mgl32.Vec3{rand.Float32(), rand.Float32(), rand.Float32()}
+	steepCol := mgl32.Vec3{rand.Float32(), rand.Float32(), rand.Float32()}
+	waterCol := mgl32.Vec3{rand.Float32(), rand.Float32(), rand.Float32()}
+	iceCol := mgl32.Vec3{0.85, 0.85, 0.85}.Add(mgl32.Vec3{rand.Float32(), rand.Float32(), rand.Float32()}.Mul(0.15))
+
+	// Set colors with offsets for additional similar colors
+	return PlanetColors{
+		shoreCol,
+		shoreCol.Add(mgl32.Vec3{rand.Float32(), rand.Float32(), rand.Float32()}.Mul(0.4)),
+		flatCol,
+		flatCol.Add(mgl32.Vec3{rand.Float32(), rand.Float32(), rand.Float32()}.Mul(0.4)),
+		steepCol,
+		steepCol.Add(mgl32.Vec3{rand.Float32(), rand.Float32(), rand.Float32()}.Mul(0.4)),
+		waterCol,
+		iceCol,
+	}
+}
@@ -0,0 +1,220 @@
+package generation
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Plate is a tectonic plate, the part of the planet surface that is closer to its center than to any other plate
+type Plate struct {
+	Center      mgl32.Vec3
+	Spin        mgl32.Vec3 // the axis the plate turns around, as long as its speed
+	Continental bool       // continental plates are land, oceanic plates are sea floor
+}
+
+// PlateSettings describes how many tectonic plates a planet has and the terrain at their boundaries.
+// Heights and widths are relative to the height of continents.
+type PlateSettings struct {
+	Count        uint32
+	LandFraction float32 // part of the plates that are continental
+
+	MountainHeight float32 // height of the mountains where plates push into each other
+	MountainWidth  float32 // how far from the boundary the mountains and rifts reach
+	RiftDepth      float32 // depth of the rifts where plates pull apart and the trenches where the sea floor sinks
+
+	Roughness float32 // how far the boundaries are bent by noise, 0 gives straight boundaries
+}
+
+// How much further from the boundary than the mountains the shelf between land and sea floor reaches
+const plateShelfWidth = 2.0
+
+/*
+PlateSettingsOf returns the tectonic plate settings of a planet shape
+
+Parameters:
+- shape: the planet shape to take the plate settings of
+
+Returns:
+- settings: the plate settings used by the default terrain of the shape
+
+Example usage:
+
+	plates := PlateSettingsOf(DefaultEarth().Shape)
+	plates.Count = 12
+*/
+func PlateSettingsOf(shape PlanetShape) PlateSettings {
+	return PlateSettings{
+		shape.PlateCount,
+		shape.PlateLandFraction,
+		shape.PlateMountainHeight,
+		shape.PlateMountainWidth,
+		shape.PlateRiftDepth,
+		shape.PlateRoughness,
+	}
+}
+
+// Checks that plates can be generated with the settings
+func (s *PlateSettings) validate() error {
+	switch {
+	case s.LandFraction < 0 || s.LandFraction > 1:
+		return fmt.Errorf("land fraction must be between 0 and 1, got %g", s.LandFraction)
+	case s.MountainWidth < 0:
+		return fmt.Errorf("mountain width must not be negative, got %g", s.MountainWidth)
+	case s.Count > 0 && s.MountainWidth == 0:
+		// The mountains are divided by their width
+		return fmt.Errorf("mountain width must be greater than 0 when there are plates")
+	case s.Roughness < 0:
+		return fmt.Errorf("roughness must not be negative, got %g", s.Roughness)
+	}
+	return nil
+}
+
+/*
+genPlates randomly places tectonic plates on the unit sphere and sets them in motion
+
+Parameters:
+- settings: the number of plates and how many of them are continental
+- rng: the random number generator to draw the plates from
+
+Returns:
+- plates: the center, motion and type of every plate
+*/
+func genPlates(settings *PlateSettings, rng *rand.Rand) []Plate {
+	plates := make([]Plate, settings.Count)
+	for i := range plates {
+		speed := rng.Float32()
+		plates[i] = Plate{randomPointOnSphere(rng), randomPointOnSphere(rng).Mul(speed), false}
+	}
+
+	// Pick the continental plates at random, so they are not always the first ones placed
+	continental := int(math.Round(float64(settings.LandFraction) * float64(len(plates))))
+	for _, i := range rng.Perm(len(plates))[:continental] {
+		plates[i].Continental = true
+	}
+	return plates
+}
+
+// A set of plates that is ready to calculate heights
+type plateField struct {
+	settings  *PlateSettings
+	plates    []Plate
+	noise     Noise // bends the boundaries
+	frequency float32
+}
+
+// How far from a boundary, relative to the mountain width, the plates on both sides still change the terrain
+const plateReach = 4.0
+
+/*
+Calculates the height of the terrain at a point from the plates around it.
+Continental plates are at 1 and oceanic plates at -1, with a shelf between them and mountains, rifts and trenches
+along the boundaries. Every part changes smoothly with the point, so the boundaries never leave steps in the terrain.
+*/
+func (f *plateField) height(point mgl32.Vec3) float32 {
+	if len(f.plates) == 0 {
+		return 0
+	}
+	settings := f.settings
+	width := settings.MountainWidth
+
+	// Bend the boundaries by looking up the plates of a point moved by noise
+	if settings.Roughness != 0 {
+		p := point.Mul(f.frequency)
+		bend := mgl32.Vec3{
+			f.noise.Sample(p.X(), p.Y(), p.Z()),
+			f.noise.Sample(p.X()+31.4, p.Y()+27.1, p.Z()+18.2),
+			f.noise.Sample(p.X()-17.3, p.Y()+41.9, p.Z()-23.6),
+		}
+		point = point.Add(bend.Mul(settings.Roughness)).Normalize()
+	}
+
+	// The point is on the plate with the closest center. How much further away the other centers are
+	// grows from 0 at the boundaries of that plate, about as fast as the distance to the boundary.
+	closest := float32(math.Inf(-1))
+	for i := range f.plates {
+		closest = float32(math.Max(float64(closest), float64(point.Dot(f.plates[i].Center))))
+	}
+	gap := func(i int) float32 {
+		return closest - point.Dot(f.plates[i].Center)
+	}
+
+	// Blend the plates into each other over the shelf
+	sum, weights := float32(0.0), float32(0.0)
+	var nearby [16]int
+	near := nearby[:0]
+	for i := range f.plates {
+		g := gap(i)
+		weight := float32(math.Exp(float64(-g / (width * plateShelfWidth * 0.5))))
+		sum += plateBase(&f.plates[i]) * weight
+		weights += weight
+
+		if g < width*plateReach {
+			near = append(near, i)
+		}
+	}
+	height := sum / weights
+
+	// Add the mountains and rifts of every boundary between two plates that are close to the point
+	for j, a := range near {
+		for _, b := range near[j+1:] {
+			// Boundaries fade out where the point is far from one of the plates, like at the far end of a boundary
+			fade := 1.0 - smoothstep(0, width*plateReach, gap(a)+gap(b))
+			height += fade * f.boundary(point, a, b)
+		}
+	}
+	return height
+}
+
+// The mountains, rifts and trenches along the boundary between two plates at a point
+func (f *plateField) boundary(point mgl32.Vec3, a, b int) float32 {
+	settings := f.settings
+
+	// Look at the boundary from the side of the point
+	normal := f.plates[a].Center.Sub(f.plates[b].Center)
+	if normal.Len() == 0 {
+		return 0
+	}
+	normal = normal.Normalize()
+	if point.Dot(normal) < 0 {
+		a, b, normal = b, a, normal.Mul(-1)
+	}
+	plate, other := &f.plates[a], &f.plates[b]
+	x := point.Dot(normal) / settings.MountainWidth
+
+	// How fast the plates move towards each other, between -1 when they pull apart and 1 when they collide
+	motion := plate.Spin.Cross(point).Sub(other.Spin.Cross(point))
+	convergence := -motion.Dot(normal) * 0.5
+
+	if convergence < 0 {
+		// Pulling apart opens a rift along the boundary
+		return settings.RiftDepth * convergence * float32(math.Exp(float64(-x*x)))
+	}
+
+	switch {
+	case plate.Continental && other.Continental:
+		// Continents fold up into a range along the boundary
+		return settings.MountainHeight * convergence * float32(math.Exp(float64(-x*x)))
+	case plate.Continental || (!other.Continental && a > b):
+		// Sea floor sinks under the continent, or under the newer sea floor, and pushes up a range or a chain of islands
+		return settings.MountainHeight * convergence * plateRange(x)
+	default:
+		// The sinking sea floor forms a trench next to the boundary
+		return -settings.RiftDepth * convergence * plateRange(x*3.0)
+	}
+}
+
+// The height of a plate away from its boundaries
+func plateBase(plate *Plate) float32 {
+	if plate.Continental {
+		return 1.0
+	}
+	return -1.0
+}
+
+// A range that rises from zero at the boundary to its peak one width inland, and fades out further in
+func plateRange(x float32) float32 {
+	return x * float32(math.Exp(float64(1.0-x)))
+}
@@ -0,0 +1,127 @@
+package generation
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestGenPlates(t *testing.T) {
+	settings := PlateSettings{Count: 20, LandFraction: 0.35, MountainWidth: 0.08}
+	plates := genPlates(&settings, rand.New(rand.NewSource(1)))
+
+	if len(plates) != 20 {
+		t.Fatalf("generated %d plates, want 20", len(plates))
+	}
+	continental := 0
+	for _, plate := range plates {
+		if plate.Continental {
+			continental++
+		}
+		if l := plate.Center.Len(); math.Abs(float64(l)-1) > 1e-5 {
+			t.Errorf("plate center %v is not on the unit sphere", plate.Center)
+		}
+		if speed := plate.Spin.Len(); speed > 1+1e-5 {
+			t.Errorf("plate turns at %v, want at most 1", speed)
+		}
+	}
+	if continental != 7 {
+		t.Errorf("%d plates are continental, want 7", continental)
+	}
+}
+
+func TestPlateSettingsValidate(t *testing.T) {
+	for _, c := range []struct {
+		settings PlateSettings
+		want     string
+	}{
+		{PlateSettings{LandFraction: 1.5, MountainWidth: 0.1}, "land fraction"},
+		{PlateSettings{MountainWidth: -0.1}, "mountain width"},
+		{PlateSettings{Count: 5}, "mountain width"},
+		{PlateSettings{MountainWidth: 0.1, Roughness: -1}, "roughness"},
+	} {
+		err := c.settings.validate()
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%+v returned %v, want an error containing %q", c.settings, err, c.want)
+		}
+	}
+
+	settings := PlateSettingsOf(DefaultEarth().Shape)
+	if err := settings.validate(); err != nil {
+		t.Errorf("the earth plates are invalid: %v", err)
+	}
+}
+
+// Two plates that meet along the great circle x = 0, turning towards each other or apart
+func twoPlates(collide, continental bool) *plateField {
+	settings := &PlateSettings{2, 0, 1.5, 0.08, 0.8, 0}
+	spin := mgl32.Vec3{0, 0, 1}
+	if !collide {
+		spin = spin.Mul(-1)
+	}
+	plates := []Plate{
+		{mgl32.Vec3{1, 0, 0}, spin, true},
+		{mgl32.Vec3{-1, 0, 0}, spin.Mul(-1), continental},
+	}
+	return &plateField{settings, plates, Simplex{}, 1}
+}
+
+func TestPlateBoundaries(t *testing.T) {
+	boundary := mgl32.Vec3{0, 1, 0}
+	inland := mgl32.Vec3{float32(math.Sin(0.08)), float32(math.Cos(0.08)), 0}
+	interior := mgl32.Vec3{1, 0, 0}
+
+	if h := twoPlates(false, true).height(interior); math.Abs(float64(h)-1) > 1e-3 {
+		t.Errorf("the middle of a continental plate is at %v, want 1", h)
+	}
+	if h := twoPlates(true, true).height(boundary); h <= 1.2 {
+		t.Errorf("colliding continents are at %v at their boundary, want a mountain range", h)
+	}
+	if h := twoPlates(false, true).height(boundary); h >= 0.8 {
+		t.Errorf("continents pulling apart are at %v at their boundary, want a rift", h)
+	}
+
+	// Sea floor sinking under a continent raises mountains inland, on the continent
+	field := twoPlates(true, false)
+	if h := field.height(inland); h <= 1.2 {
+		t.Errorf("a continent colliding with sea floor is at %v inland, want a mountain range", h)
+	}
+	if a, b := field.height(boundary), field.height(mgl32.Vec3{-inland.X(), inland.Y(), 0}); b >= a {
+		t.Errorf("the sea floor is at %v next to the boundary and %v at it, want a trench", b, a)
+	}
+}
+
+func TestPlateHeightIsSmooth(t *testing.T) {
+	settings := PlateSettings{20, 0.35, 1.5, 0.08, 0.8, 0.15}
+	field := &plateField{&settings, genPlates(&settings, rand.New(rand.NewSource(1))), Simplex{}, 1}
+	rng := rand.New(rand.NewSource(2))
+
+	// Plates meet without steps, so points next to each other always have about the same height
+	for i := 0; i < 20000; i++ {
+		point := randomPointOnSphere(rng)
+		next := point.Add(randomPointOnSphere(rng).Mul(1e-4)).Normalize()
+		if a, b := field.height(point), field.height(next); math.Abs(float64(a-b)) > 0.01 {
+			t.Fatalf("heights at %v and %v are %v and %v, want a smooth surface", point, next, a, b)
+		}
+	}
+}
+
+func TestDefaultTerrainWithPlates(t *testing.T) {
+	shape := DefaultEarth().Shape
+	directions := []mgl32.Vec3{{0, 1, 0}, {1, 0, 0}, {0, 0, -1}, {0.6, 0.8, 0}}
+	without := genHeights(t, directions, shape)
+
+	shape.PlateCount = 20
+	with := genHeights(t, directions, shape)
+
+	changed := false
+	for i := range directions {
+		changed = changed || with[i] != without[i]
+	}
+	if !changed {
+		t.Error("plates did not change the terrain")
+	}
+}
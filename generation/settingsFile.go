@@ -0,0 +1,792 @@
+package generation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"stensvad-ossianst-melvinbe-project/internal/fileformat"
+)
+
+// On-disk representation of PlanetSettings with named keys, documented in docs/planet-settings.md
+type settingsFile struct {
+	Preset string `json:"preset,omitempty" yaml:"preset,omitempty" toml:"preset,omitempty"`
+
+	Shape  shapeFile  `json:"shape" yaml:"shape" toml:"shape"`
+	Colors colorsFile `json:"colors" yaml:"colors" toml:"colors"`
+
+	HasAtmosphere bool `json:"has_atmosphere" yaml:"has_atmosphere" toml:"has_atmosphere"`
+	HasOcean      bool `json:"has_ocean" yaml:"has_ocean" toml:"has_ocean"`
+
+	Texture   string `json:"texture" yaml:"texture" toml:"texture"`
+	NormalMap string `json:"normal_map" yaml:"normal_map" toml:"normal_map"`
+	Shader    string `json:"shader" yaml:"shader" toml:"shader"`
+
+	TextureScale   float32 `json:"texture_scale" yaml:"texture_scale" toml:"texture_scale"`
+	NormalMapScale float32 `json:"normal_map_scale" yaml:"normal_map_scale" toml:"normal_map_scale"`
+}
+
+type shapeFile struct {
+	Seed       int64   `json:"seed" yaml:"seed" toml:"seed"`
+	Radius     float32 `json:"radius" yaml:"radius" toml:"radius"`
+	Resolution uint32  `json:"resolution" yaml:"resolution" toml:"resolution"`
+	Amplitude  float32 `json:"amplitude" yaml:"amplitude" toml:"amplitude"`
+	Frequency  float32 `json:"frequency" yaml:"frequency" toml:"frequency"`
+
+	Ocean struct {
+		Depth      float32 `json:"depth" yaml:"depth" toml:"depth"`
+		FloorDepth float32 `json:"floor_depth" yaml:"floor_depth" toml:"floor_depth"`
+		Smoothness float32 `json:"smoothness" yaml:"smoothness" toml:"smoothness"`
+	} `json:"ocean" yaml:"ocean" toml:"ocean"`
+
+	Continent struct {
+		Amplitude float32 `json:"amplitude" yaml:"amplitude" toml:"amplitude"`
+		Frequency float32 `json:"frequency" yaml:"frequency" toml:"frequency"`
+		Noise     string  `json:"noise" yaml:"noise" toml:"noise"`
+		Warp      float32 `json:"warp" yaml:"warp" toml:"warp"`
+
+		Octaves       uint32  `json:"octaves" yaml:"octaves" toml:"octaves"`
+		Lacunarity    float32 `json:"lacunarity" yaml:"lacunarity" toml:"lacunarity"`
+		Persistence   float32 `json:"persistence" yaml:"persistence" toml:"persistence"`
+		RotateOctaves bool    `json:"rotate_octaves" yaml:"rotate_octaves" toml:"rotate_octaves"`
+	} `json:"continent" yaml:"continent" toml:"continent"`
+
+	Plates struct {
+		Count          uint32  `json:"count" yaml:"count" toml:"count"`
+		LandFraction   float32 `json:"land_fraction" yaml:"land_fraction" toml:"land_fraction"`
+		MountainHeight float32 `json:"mountain_height" yaml:"mountain_height" toml:"mountain_height"`
+		MountainWidth  float32 `json:"mountain_width" yaml:"mountain_width" toml:"mountain_width"`
+		RiftDepth      float32 `json:"rift_depth" yaml:"rift_depth" toml:"rift_depth"`
+		Roughness      float32 `json:"roughness" yaml:"roughness" toml:"roughness"`
+	} `json:"plates" yaml:"plates" toml:"plates"`
+
+	Mountain struct {
+		Amplitude  float32 `json:"amplitude" yaml:"amplitude" toml:"amplitude"`
+		Frequency  float32 `json:"frequency" yaml:"frequency" toml:"frequency"`
+		Smoothness float32 `json:"smoothness" yaml:"smoothness" toml:"smoothness"`
+		Noise      string  `json:"noise" yaml:"noise" toml:"noise"`
+		Warp       float32 `json:"warp" yaml:"warp" toml:"warp"`
+
+		Octaves       uint32  `json:"octaves" yaml:"octaves" toml:"octaves"`
+		Lacunarity    float32 `json:"lacunarity" yaml:"lacunarity" toml:"lacunarity"`
+		Persistence   float32 `json:"persistence" yaml:"persistence" toml:"persistence"`
+		RotateOctaves bool    `json:"rotate_octaves" yaml:"rotate_octaves" toml:"rotate_octaves"`
+	} `json:"mountain" yaml:"mountain" toml:"mountain"`
+
+	MountainMask struct {
+		Amplitude  float32 `json:"amplitude" yaml:"amplitude" toml:"amplitude"`
+		Smoothness float32 `json:"smoothness" yaml:"smoothness" toml:"smoothness"`
+		Offset     float32 `json:"offset" yaml:"offset" toml:"offset"`
+		Noise      string  `json:"noise" yaml:"noise" toml:"noise"`
+		Warp       float32 `json:"warp" yaml:"warp" toml:"warp"`
+
+		Octaves       uint32  `json:"octaves" yaml:"octaves" toml:"octaves"`
+		Lacunarity    float32 `json:"lacunarity" yaml:"lacunarity" toml:"lacunarity"`
+		Persistence   float32 `json:"persistence" yaml:"persistence" toml:"persistence"`
+		RotateOctaves bool    `json:"rotate_octaves" yaml:"rotate_octaves" toml:"rotate_octaves"`
+	} `json:"mountain_mask" yaml:"mountain_mask" toml:"mountain_mask"`
+
+	Craters struct {
+		Count        uint32  `json:"count" yaml:"count" toml:"count"`
+		RimWidth     float32 `json:"rim_width" yaml:"rim_width" toml:"rim_width"`
+		RimSteepness float32 `json:"rim_steepness" yaml:"rim_steepness" toml:"rim_steepness"`
+		Smoothness   float32 `json:"smoothness" yaml:"smoothness" toml:"smoothness"`
+		FloorHeight  float32 `json:"floor_height" yaml:"floor_height" toml:"floor_height"`
+
+		MinRadius     float32 `json:"min_radius" yaml:"min_radius" toml:"min_radius"`
+		MaxRadius     float32 `json:"max_radius" yaml:"max_radius" toml:"max_radius"`
+		SizeExponent  float32 `json:"size_exponent" yaml:"size_exponent" toml:"size_exponent"`
+		PeakHeight    float32 `json:"peak_height" yaml:"peak_height" toml:"peak_height"`
+		PeakMinRadius float32 `json:"peak_min_radius" yaml:"peak_min_radius" toml:"peak_min_radius"`
+		RimNoise      float32 `json:"rim_noise" yaml:"rim_noise" toml:"rim_noise"`
+		EjectaHeight  float32 `json:"ejecta_height" yaml:"ejecta_height" toml:"ejecta_height"`
+		EjectaWidth   float32 `json:"ejecta_width" yaml:"ejecta_width" toml:"ejecta_width"`
+		Spacing       float32 `json:"spacing" yaml:"spacing" toml:"spacing"`
+	} `json:"craters" yaml:"craters" toml:"craters"`
+
+	Erosion struct {
+		Iterations  uint32  `json:"iterations" yaml:"iterations" toml:"iterations"`
+		Rain        float32 `json:"rain" yaml:"rain" toml:"rain"`
+		Capacity    float32 `json:"capacity" yaml:"capacity" toml:"capacity"`
+		Evaporation float32 `json:"evaporation" yaml:"evaporation" toml:"evaporation"`
+		Rate        float32 `json:"rate" yaml:"rate" toml:"rate"`
+		Deposition  float32 `json:"deposition" yaml:"deposition" toml:"deposition"`
+
+		ThermalIterations uint32  `json:"thermal_iterations" yaml:"thermal_iterations" toml:"thermal_iterations"`
+		Talus             float32 `json:"talus" yaml:"talus" toml:"talus"`
+		ThermalRate       float32 `json:"thermal_rate" yaml:"thermal_rate" toml:"thermal_rate"`
+	} `json:"erosion" yaml:"erosion" toml:"erosion"`
+
+	Rivers struct {
+		MinArea float32 `json:"min_area" yaml:"min_area" toml:"min_area"`
+		Depth   float32 `json:"depth" yaml:"depth" toml:"depth"`
+		Width   float32 `json:"width" yaml:"width" toml:"width"`
+	} `json:"rivers" yaml:"rivers" toml:"rivers"`
+
+	Climate struct {
+		Enabled            bool    `json:"enabled" yaml:"enabled" toml:"enabled"`
+		AxialTilt          float32 `json:"axial_tilt" yaml:"axial_tilt" toml:"axial_tilt"`
+		EquatorTemperature float32 `json:"equator_temperature" yaml:"equator_temperature" toml:"equator_temperature"`
+		PoleTemperature    float32 `json:"pole_temperature" yaml:"pole_temperature" toml:"pole_temperature"`
+		LapseRate          float32 `json:"lapse_rate" yaml:"lapse_rate" toml:"lapse_rate"`
+		Rainfall           float32 `json:"rainfall" yaml:"rainfall" toml:"rainfall"`
+		MoistureReach      float32 `json:"moisture_reach" yaml:"moisture_reach" toml:"moisture_reach"`
+		RainShadow         float32 `json:"rain_shadow" yaml:"rain_shadow" toml:"rain_shadow"`
+	} `json:"climate" yaml:"climate" toml:"climate"`
+
+	Ice struct {
+		Enabled     bool    `json:"enabled" yaml:"enabled" toml:"enabled"`
+		CapLatitude float32 `json:"cap_latitude" yaml:"cap_latitude" toml:"cap_latitude"`
+		Snowline    float32 `json:"snowline" yaml:"snowline" toml:"snowline"`
+		Roughness   float32 `json:"roughness" yaml:"roughness" toml:"roughness"`
+	} `json:"ice" yaml:"ice" toml:"ice"`
+
+	LOD struct {
+		Levels uint32 `json:"levels" yaml:"levels" toml:"levels"`
+	} `json:"lod" yaml:"lod" toml:"lod"`
+
+	Terrain []terrainNodeFile `json:"terrain,omitempty" yaml:"terrain,omitempty" toml:"terrain,omitempty"`
+}
+
+type terrainNodeFile struct {
+	Name   string   `json:"name" yaml:"name" toml:"name"`
+	Type   string   `json:"type" yaml:"type" toml:"type"`
+	Inputs []string `json:"inputs,omitempty" yaml:"inputs,omitempty,flow" toml:"inputs,omitempty"`
+
+	Noise     string  `json:"noise,omitempty" yaml:"noise,omitempty" toml:"noise,omitempty"`
+	Warp      float32 `json:"warp,omitempty" yaml:"warp,omitempty" toml:"warp,omitzero"`
+	Amplitude float32 `json:"amplitude,omitempty" yaml:"amplitude,omitempty" toml:"amplitude,omitzero"`
+	Frequency float32 `json:"frequency,omitempty" yaml:"frequency,omitempty" toml:"frequency,omitzero"`
+
+	Octaves       uint32  `json:"octaves,omitempty" yaml:"octaves,omitempty" toml:"octaves,omitzero"`
+	Lacunarity    float32 `json:"lacunarity,omitempty" yaml:"lacunarity,omitempty" toml:"lacunarity,omitzero"`
+	Persistence   float32 `json:"persistence,omitempty" yaml:"persistence,omitempty" toml:"persistence,omitzero"`
+	RotateOctaves bool    `json:"rotate_octaves,omitempty" yaml:"rotate_octaves,omitempty" toml:"rotate_octaves,omitempty"`
+
+	Value      float32      `json:"value,omitempty" yaml:"value,omitempty" toml:"value,omitzero"`
+	Smoothness float32      `json:"smoothness,omitempty" yaml:"smoothness,omitempty" toml:"smoothness,omitzero"`
+	Points     [][2]float32 `json:"points,omitempty" yaml:"points,omitempty,flow" toml:"points,omitempty"`
+
+	Count         uint32  `json:"count,omitempty" yaml:"count,omitempty" toml:"count,omitzero"`
+	MinRadius     float32 `json:"min_radius,omitempty" yaml:"min_radius,omitempty" toml:"min_radius,omitzero"`
+	MaxRadius     float32 `json:"max_radius,omitempty" yaml:"max_radius,omitempty" toml:"max_radius,omitzero"`
+	SizeExponent  float32 `json:"size_exponent,omitempty" yaml:"size_exponent,omitempty" toml:"size_exponent,omitzero"`
+	RimWidth      float32 `json:"rim_width,omitempty" yaml:"rim_width,omitempty" toml:"rim_width,omitzero"`
+	RimSteepness  float32 `json:"rim_steepness,omitempty" yaml:"rim_steepness,omitempty" toml:"rim_steepness,omitzero"`
+	RimNoise      float32 `json:"rim_noise,omitempty" yaml:"rim_noise,omitempty" toml:"rim_noise,omitzero"`
+	FloorHeight   float32 `json:"floor_height,omitempty" yaml:"floor_height,omitempty" toml:"floor_height,omitzero"`
+	PeakHeight    float32 `json:"peak_height,omitempty" yaml:"peak_height,omitempty" toml:"peak_height,omitzero"`
+	PeakMinRadius float32 `json:"peak_min_radius,omitempty" yaml:"peak_min_radius,omitempty" toml:"peak_min_radius,omitzero"`
+	EjectaHeight  float32 `json:"ejecta_height,omitempty" yaml:"ejecta_height,omitempty" toml:"ejecta_height,omitzero"`
+	EjectaWidth   float32 `json:"ejecta_width,omitempty" yaml:"ejecta_width,omitempty" toml:"ejecta_width,omitzero"`
+	Spacing       float32 `json:"spacing,omitempty" yaml:"spacing,omitempty" toml:"spacing,omitzero"`
+
+	LandFraction   float32 `json:"land_fraction,omitempty" yaml:"land_fraction,omitempty" toml:"land_fraction,omitzero"`
+	MountainHeight float32 `json:"mountain_height,omitempty" yaml:"mountain_height,omitempty" toml:"mountain_height,omitzero"`
+	MountainWidth  float32 `json:"mountain_width,omitempty" yaml:"mountain_width,omitempty" toml:"mountain_width,omitzero"`
+	RiftDepth      float32 `json:"rift_depth,omitempty" yaml:"rift_depth,omitempty" toml:"rift_depth,omitzero"`
+	Roughness      float32 `json:"roughness,omitempty" yaml:"roughness,omitempty" toml:"roughness,omitzero"`
+}
+
+type colorsFile struct {
+	ShoreLow  [3]float32 `json:"shore_low" yaml:"shore_low,flow" toml:"shore_low"`
+	ShoreHigh [3]float32 `json:"shore_high" yaml:"shore_high,flow" toml:"shore_high"`
+	FlatLow   [3]float32 `json:"flat_low" yaml:"flat_low,flow" toml:"flat_low"`
+	FlatHigh  [3]float32 `json:"flat_high" yaml:"flat_high,flow" toml:"flat_high"`
+	SteepLow  [3]float32 `json:"steep_low" yaml:"steep_low,flow" toml:"steep_low"`
+	SteepHigh [3]float32 `json:"steep_high" yaml:"steep_high,flow" toml:"steep_high"`
+	Water     [3]float32 `json:"water" yaml:"water,flow" toml:"water"`
+	Ice       [3]float32 `json:"ice" yaml:"ice,flow" toml:"ice"`
+}
+
+/*
+LoadPlanetSettings reads planet settings from a JSON, YAML or TOML file, picked by file extension.
+Keys missing from the file are taken from the preset named by the "preset" key, or from
+DefaultEarth if there is none.
+
+Parameters:
+- path: the settings file to read
+
+Returns:
+- settings: the planet settings described by the file
+- err: an error naming the offending key if the file could not be read or is invalid
+
+Example usage:
+
+	settings, err := LoadPlanetSettings("res/planets/frozen.yaml")
+*/
+func LoadPlanetSettings(path string) (PlanetSettings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PlanetSettings{}, err
+	}
+
+	return ParsePlanetSettings(path, data)
+}
+
+// ParsePlanetSettings is like LoadPlanetSettings but decodes settings that have already been read,
+// the format is picked from the extension of path
+func ParsePlanetSettings(path string, data []byte) (PlanetSettings, error) {
+	// Look up the preset first so that its values act as defaults for missing keys
+	var header struct {
+		Preset string `json:"preset" yaml:"preset" toml:"preset"`
+	}
+	if err := fileformat.Decode(path, data, &header, false); err != nil {
+		return PlanetSettings{}, err
+	}
+
+	base, err := Preset(header.Preset)
+	if err != nil {
+		return PlanetSettings{}, fmt.Errorf("%s: preset: %v", path, err)
+	}
+
+	file := newSettingsFile(base)
+	if err := fileformat.Decode(path, data, &file, true); err != nil {
+		return PlanetSettings{}, err
+	}
+
+	if err := file.validate(); err != nil {
+		return PlanetSettings{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return file.settings(), nil
+}
+
+/*
+SavePlanetSettings writes planet settings to a JSON, YAML or TOML file, picked by file extension.
+Every key is written, so the file does not depend on any preset.
+
+Parameters:
+- path: the settings file to write
+- settings: the planet settings to save
+
+Example usage:
+
+	err := SavePlanetSettings("earth.toml", DefaultEarth())
+*/
+func SavePlanetSettings(path string, settings PlanetSettings) error {
+	data, err := fileformat.Encode(path, newSettingsFile(settings))
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// SettingsOverrides are planet settings keys that replace those of other settings, with the same keys as a settings file
+type SettingsOverrides map[string]interface{}
+
+// UnmarshalTOML decodes the overrides as they are, so that the toml decoder does not report their keys as unknown
+func (o *SettingsOverrides) UnmarshalTOML(data interface{}) error {
+	table, ok := data.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected a table of planet settings")
+	}
+	*o = table
+	return nil
+}
+
+// Apply returns base with the keys of the overrides replaced
+func (o SettingsOverrides) Apply(base PlanetSettings) (PlanetSettings, error) {
+	if len(o) == 0 {
+		return base, nil
+	}
+
+	// Overrides may come from any file format, so they are passed on through json
+	data, err := json.Marshal(o)
+	if err != nil {
+		return PlanetSettings{}, err
+	}
+
+	file := newSettingsFile(base)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return PlanetSettings{}, err
+	}
+
+	if err := file.validate(); err != nil {
+		return PlanetSettings{}, err
+	}
+
+	return file.settings(), nil
+}
+
+// Preset returns the settings of a named preset, or DefaultEarth if no name is given
+func Preset(name string) (PlanetSettings, error) {
+	if name == "" {
+		return DefaultEarth(), nil
+	}
+
+	newSettings, ok := presets[name]
+	if !ok {
+		return PlanetSettings{}, fmt.Errorf("unknown preset %q, expected one of %s", name, strings.Join(PresetNames(), ", "))
+	}
+
+	return newSettings(), nil
+}
+
+// PresetNames returns the names of every preset in alphabetical order
+func PresetNames() []string {
+	names := []string{}
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Converts planet settings to their on-disk representation
+func newSettingsFile(s PlanetSettings) settingsFile {
+	f := settingsFile{
+		HasAtmosphere:  s.HasAtmosphere,
+		HasOcean:       s.HasOcean,
+		Texture:        s.TexturePath,
+		NormalMap:      s.NormalMapPath,
+		Shader:         s.ShaderPath,
+		TextureScale:   s.TextureScale,
+		NormalMapScale: s.NormalMapScale,
+	}
+
+	shape := &f.Shape
+	shape.Seed = s.Shape.Seed
+	shape.Radius = s.Shape.Radius
+	shape.Resolution = s.Shape.Res
+	shape.Amplitude = s.Shape.Amplitude
+	shape.Frequency = s.Shape.Frequency
+
+	shape.Ocean.Depth = s.Shape.OceanDepth
+	shape.Ocean.FloorDepth = s.Shape.OceanFloorDepth
+	shape.Ocean.Smoothness = s.Shape.OceanSmoothness
+
+	shape.Continent.Amplitude = s.Shape.ContinentAmplitude
+	shape.Continent.Frequency = s.Shape.ContinentFrequency
+	shape.Continent.Noise = string(s.Shape.ContinentNoise.Type)
+	shape.Continent.Warp = s.Shape.ContinentNoise.Warp
+	shape.Continent.Octaves = s.Shape.ContinentFractal.Octaves
+	shape.Continent.Lacunarity = s.Shape.ContinentFractal.Lacunarity
+	shape.Continent.Persistence = s.Shape.ContinentFractal.Persistence
+	shape.Continent.RotateOctaves = s.Shape.ContinentFractal.Rotate
+
+	shape.Plates.Count = s.Shape.PlateCount
+	shape.Plates.LandFraction = s.Shape.PlateLandFraction
+	shape.Plates.MountainHeight = s.Shape.PlateMountainHeight
+	shape.Plates.MountainWidth = s.Shape.PlateMountainWidth
+	shape.Plates.RiftDepth = s.Shape.PlateRiftDepth
+	shape.Plates.Roughness = s.Shape.PlateRoughness
+
+	shape.Mountain.Amplitude = s.Shape.MountainAmplitude
+	shape.Mountain.Frequency = s.Shape.MountainFrequency
+	shape.Mountain.Smoothness = s.Shape.MountainSmoothness
+	shape.Mountain.Noise = string(s.Shape.MountainNoise.Type)
+	shape.Mountain.Warp = s.Shape.MountainNoise.Warp
+	shape.Mountain.Octaves = s.Shape.MountainFractal.Octaves
+	shape.Mountain.Lacunarity = s.Shape.MountainFractal.Lacunarity
+	shape.Mountain.Persistence = s.Shape.MountainFractal.Persistence
+	shape.Mountain.RotateOctaves = s.Shape.MountainFractal.Rotate
+
+	shape.MountainMask.Amplitude = s.Shape.MountainMaskAmplitude
+	shape.MountainMask.Smoothness = s.Shape.MountainMaskSmoothness
+	shape.MountainMask.Offset = s.Shape.MountainMaskOffset
+	shape.MountainMask.Noise = string(s.Shape.MountainMaskNoise.Type)
+	shape.MountainMask.Warp = s.Shape.MountainMaskNoise.Warp
+	shape.MountainMask.Octaves = s.Shape.MountainMaskFractal.Octaves
+	shape.MountainMask.Lacunarity = s.Shape.MountainMaskFractal.Lacunarity
+	shape.MountainMask.Persistence = s.Shape.MountainMaskFractal.Persistence
+	shape.MountainMask.RotateOctaves = s.Shape.MountainMaskFractal.Rotate
+
+	shape.Craters.Count = s.Shape.NumCraters
+	shape.Craters.RimWidth = s.Shape.CraterRimWidth
+	shape.Craters.RimSteepness = s.Shape.CraterRimSteepness
+	shape.Craters.Smoothness = s.Shape.CraterSmoothness
+	shape.Craters.FloorHeight = s.Shape.CraterFloorHeight
+	shape.Craters.MinRadius = s.Shape.CraterMinRadius
+	shape.Craters.MaxRadius = s.Shape.CraterMaxRadius
+	shape.Craters.SizeExponent = s.Shape.CraterSizeExponent
+	shape.Craters.PeakHeight = s.Shape.CraterPeakHeight
+	shape.Craters.PeakMinRadius = s.Shape.CraterPeakMinRadius
+	shape.Craters.RimNoise = s.Shape.CraterRimNoise
+	shape.Craters.EjectaHeight = s.Shape.CraterEjectaHeight
+	shape.Craters.EjectaWidth = s.Shape.CraterEjectaWidth
+	shape.Craters.Spacing = s.Shape.CraterSpacing
+
+	shape.Erosion.Iterations = s.Shape.ErosionIterations
+	shape.Erosion.Rain = s.Shape.ErosionRain
+	shape.Erosion.Capacity = s.Shape.ErosionCapacity
+	shape.Erosion.Evaporation = s.Shape.ErosionEvaporation
+	shape.Erosion.Rate = s.Shape.ErosionRate
+	shape.Erosion.Deposition = s.Shape.ErosionDeposition
+	shape.Erosion.ThermalIterations = s.Shape.ThermalIterations
+	shape.Erosion.Talus = s.Shape.ThermalTalus
+	shape.Erosion.ThermalRate = s.Shape.ThermalRate
+
+	shape.Rivers.MinArea = s.Shape.RiverMinArea
+	shape.Rivers.Depth = s.Shape.RiverDepth
+	shape.Rivers.Width = s.Shape.RiverWidth
+
+	shape.Climate.Enabled = s.Shape.HasClimate
+	shape.Climate.AxialTilt = s.Shape.AxialTilt
+	shape.Climate.EquatorTemperature = s.Shape.EquatorTemperature
+	shape.Climate.PoleTemperature = s.Shape.PoleTemperature
+	shape.Climate.LapseRate = s.Shape.LapseRate
+	shape.Climate.Rainfall = s.Shape.Rainfall
+	shape.Climate.MoistureReach = s.Shape.MoistureReach
+	shape.Climate.RainShadow = s.Shape.RainShadow
+
+	shape.Ice.Enabled = s.Shape.HasIce
+	shape.Ice.CapLatitude = s.Shape.IceCapLatitude
+	shape.Ice.Snowline = s.Shape.SnowlineHeight
+	shape.Ice.Roughness = s.Shape.IceRoughness
+
+	shape.LOD.Levels = s.Shape.LODLevels
+
+	for _, node := range s.Shape.Terrain {
+		// Crater nodes keep their smoothness with the rest of the crater settings
+		smoothness := node.Smoothness
+		if node.Type == CraterNode {
+			smoothness = node.Craters.Smoothness
+		}
+		// Crater and plate nodes share the count key
+		count := node.Craters.Count
+		if node.Type == PlateNode {
+			count = node.Plates.Count
+		}
+
+		shape.Terrain = append(shape.Terrain, terrainNodeFile{
+			node.Name,
+			string(node.Type),
+			node.Inputs,
+			string(node.Noise.Type),
+			node.Noise.Warp,
+			node.Amplitude,
+			node.Frequency,
+			node.Fractal.Octaves,
+			node.Fractal.Lacunarity,
+			node.Fractal.Persistence,
+			node.Fractal.Rotate,
+			node.Value,
+			smoothness,
+			node.Points,
+			count,
+			node.Craters.MinRadius,
+			node.Craters.MaxRadius,
+			node.Craters.SizeExponent,
+			node.Craters.RimWidth,
+			node.Craters.RimSteepness,
+			node.Craters.RimNoise,
+			node.Craters.FloorHeight,
+			node.Craters.PeakHeight,
+			node.Craters.PeakMinRadius,
+			node.Craters.EjectaHeight,
+			node.Craters.EjectaWidth,
+			node.Craters.Spacing,
+			node.Plates.LandFraction,
+			node.Plates.MountainHeight,
+			node.Plates.MountainWidth,
+			node.Plates.RiftDepth,
+			node.Plates.Roughness,
+		})
+	}
+
+	f.Colors = colorsFile{
+		s.Colors.ShoreColLow,
+		s.Colors.ShoreColHigh,
+		s.Colors.FlatColLow,
+		s.Colors.FlatColHigh,
+		s.Colors.SteepColLow,
+		s.Colors.SteepColHigh,
+		s.Colors.WaterCol,
+		s.Colors.IceCol,
+	}
+
+	return f
+}
+
+// Converts the on-disk representation back to planet settings
+func (f *settingsFile) settings() PlanetSettings {
+	shape := &f.Shape
+
+	return PlanetSettings{
+		PlanetShape{
+			shape.Seed,
+			shape.Radius,
+			shape.Resolution,
+			shape.Amplitude,
+			shape.Frequency,
+
+			shape.Ocean.Depth,
+			shape.Ocean.FloorDepth,
+			shape.Ocean.Smoothness,
+
+			shape.Continent.Amplitude,
+			shape.Continent.Frequency,
+			NoiseSettings{NoiseType(shape.Continent.Noise), shape.Continent.Warp},
+			FractalSettings{shape.Continent.Octaves, shape.Continent.Lacunarity, shape.Continent.Persistence, shape.Continent.RotateOctaves},
+
+			shape.Plates.Count,
+			shape.Plates.LandFraction,
+			shape.Plates.MountainHeight,
+			shape.Plates.MountainWidth,
+			shape.Plates.RiftDepth,
+			shape.Plates.Roughness,
+
+			shape.Mountain.Amplitude,
+			shape.Mountain.Frequency,
+			shape.Mountain.Smoothness,
+			NoiseSettings{NoiseType(shape.Mountain.Noise), shape.Mountain.Warp},
+			FractalSettings{shape.Mountain.Octaves, shape.Mountain.Lacunarity, shape.Mountain.Persistence, shape.Mountain.RotateOctaves},
+
+			shape.MountainMask.Amplitude,
+			shape.MountainMask.Smoothness,
+			shape.MountainMask.Offset,
+			NoiseSettings{NoiseType(shape.MountainMask.Noise), shape.MountainMask.Warp},
+			FractalSettings{shape.MountainMask.Octaves, shape.MountainMask.Lacunarity, shape.MountainMask.Persistence, shape.MountainMask.RotateOctaves},
+
+			shape.Craters.Count,
+			shape.Craters.RimWidth,
+			shape.Craters.RimSteepness,
+			shape.Craters.Smoothness,
+			shape.Craters.FloorHeight,
+			shape.Craters.MinRadius,
+			shape.Craters.MaxRadius,
+			shape.Craters.SizeExponent,
+			shape.Craters.PeakHeight,
+			shape.Craters.PeakMinRadius,
+			shape.Craters.RimNoise,
+			shape.Craters.EjectaHeight,
+			shape.Craters.EjectaWidth,
+			shape.Craters.Spacing,
+
+			shape.Erosion.Iterations,
+			shape.Erosion.Rain,
+			shape.Erosion.Capacity,
+			shape.Erosion.Evaporation,
+			shape.Erosion.Rate,
+			shape.Erosion.Deposition,
+			shape.Erosion.ThermalIterations,
+			shape.Erosion.Talus,
+			shape.Erosion.ThermalRate,
+
+			shape.Rivers.MinArea,
+			shape.Rivers.Depth,
+			shape.Rivers.Width,
+
+			shape.Climate.Enabled,
+			shape.Climate.AxialTilt,
+			shape.Climate.EquatorTemperature,
+			shape.Climate.PoleTemperature,
+			shape.Climate.LapseRate,
+			shape.Climate.Rainfall,
+			shape.Climate.MoistureReach,
+			shape.Climate.RainShadow,
+
+			shape.Ice.Enabled,
+			shape.Ice.CapLatitude,
+			shape.Ice.Snowline,
+			shape.Ice.Roughness,
+
+			shape.LOD.Levels,
+
+			f.terrain(),
+		},
+
+		PlanetColors{
+			mgl32.Vec3(f.Colors.ShoreLow),
+			mgl32.Vec3(f.Colors.ShoreHigh),
+			mgl32.Vec3(f.Colors.FlatLow),
+			mgl32.Vec3(f.Colors.FlatHigh),
+			mgl32.Vec3(f.Colors.SteepLow),
+			mgl32.Vec3(f.Colors.SteepHigh),
+			mgl32.Vec3(f.Colors.Water),
+			mgl32.Vec3(f.Colors.Ice),
+		},
+
+		f.HasAtmosphere,
+		f.HasOcean,
+
+		f.Texture,
+		f.NormalMap,
+		f.Shader,
+
+		f.TextureScale,
+		f.NormalMapScale,
+	}
+}
+
+// Converts the terrain nodes of the file, a file without nodes uses the default terrain
+func (f *settingsFile) terrain() []TerrainNode {
+	if len(f.Shape.Terrain) == 0 {
+		return nil
+	}
+
+	nodes := make([]TerrainNode, len(f.Shape.Terrain))
+	for i, node := range f.Shape.Terrain {
+		smoothness, craterSmoothness := node.Smoothness, float32(0.0)
+		if TerrainNodeType(node.Type) == CraterNode {
+			smoothness, craterSmoothness = 0.0, node.Smoothness
+		}
+		craterCount, plateCount := node.Count, uint32(0)
+		if TerrainNodeType(node.Type) == PlateNode {
+			craterCount, plateCount = 0, node.Count
+		}
+
+		nodes[i] = TerrainNode{
+			node.Name,
+			TerrainNodeType(node.Type),
+			node.Inputs,
+			NoiseSettings{NoiseType(node.Noise), node.Warp},
+			FractalSettings{node.Octaves, node.Lacunarity, node.Persistence, node.RotateOctaves},
+			node.Amplitude,
+			node.Frequency,
+			node.Value,
+			smoothness,
+			node.Points,
+			CraterSettings{
+				craterCount,
+				node.MinRadius,
+				node.MaxRadius,
+				node.SizeExponent,
+				node.RimWidth,
+				node.RimSteepness,
+				node.RimNoise,
+				craterSmoothness,
+				node.FloorHeight,
+				node.PeakHeight,
+				node.PeakMinRadius,
+				node.EjectaHeight,
+				node.EjectaWidth,
+				node.Spacing,
+			},
+			PlateSettings{
+				plateCount,
+				node.LandFraction,
+				node.MountainHeight,
+				node.MountainWidth,
+				node.RiftDepth,
+				node.Roughness,
+			},
+		}
+	}
+	return nodes
+}
+
+// Checks that every value can be used to generate and draw a planet.
+// Every problem is reported with the key it was found at.
+func (f *settingsFile) validate() error {
+	errs := []error{}
+	check := func(ok bool, key, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	shape := &f.Shape
+	settings := f.settings()
+	check(shape.Radius > 0, "shape.radius", "must be greater than 0, got %g", shape.Radius)
+	check(shape.Resolution > 0, "shape.resolution", "must be greater than 0")
+	// The mesh is divided by the resolution scaled by the radius, which has to give at least one triangle per face
+	check(!(shape.Radius > 0) || shape.Resolution == 0 || float32(shape.Resolution)*shape.Radius >= 1, "shape.resolution", "times shape.radius must be at least 1, got %g", float32(shape.Resolution)*shape.Radius)
+	check(shape.Frequency >= 0, "shape.frequency", "must not be negative, got %g", shape.Frequency)
+	check(shape.Ocean.FloorDepth >= 0, "shape.ocean.floor_depth", "must not be negative, got %g", shape.Ocean.FloorDepth)
+	check(shape.Ocean.Smoothness >= 0, "shape.ocean.smoothness", "must not be negative, got %g", shape.Ocean.Smoothness)
+	check(shape.Continent.Frequency >= 0, "shape.continent.frequency", "must not be negative, got %g", shape.Continent.Frequency)
+	// Plates and craters are checked like they are when they are nodes of a terrain graph
+	plates, craters := PlateSettingsOf(settings.Shape), CraterSettingsOf(settings.Shape)
+	err := plates.validate()
+	check(err == nil, "shape.plates", "%v", err)
+	check(shape.Mountain.Frequency >= 0, "shape.mountain.frequency", "must not be negative, got %g", shape.Mountain.Frequency)
+	check(shape.Mountain.Smoothness >= 0, "shape.mountain.smoothness", "must not be negative, got %g", shape.Mountain.Smoothness)
+	check(shape.MountainMask.Smoothness >= 0, "shape.mountain_mask.smoothness", "must not be negative, got %g", shape.MountainMask.Smoothness)
+	// Lists keep the errors in the same order every time, unlike maps
+	for _, noise := range []struct {
+		key, name string
+	}{
+		{"shape.continent.noise", shape.Continent.Noise},
+		{"shape.mountain.noise", shape.Mountain.Noise},
+		{"shape.mountain_mask.noise", shape.MountainMask.Noise},
+	} {
+		_, err := NoiseSettings{NoiseType(noise.name), 0}.Noise(nil)
+		check(err == nil, noise.key, "must be one of %v, got %q", NoiseTypes, noise.name)
+	}
+	for _, fractal := range []struct {
+		key      string
+		settings FractalSettings
+	}{
+		{"shape.continent", settings.Shape.ContinentFractal},
+		{"shape.mountain", settings.Shape.MountainFractal},
+		{"shape.mountain_mask", settings.Shape.MountainMaskFractal},
+	} {
+		err := fractal.settings.validate()
+		check(err == nil, fractal.key, "%v", err)
+	}
+	if nodes := f.terrain(); nodes != nil {
+		err := validateTerrain(nodes)
+		check(err == nil, "shape.terrain", "%v", err)
+	}
+	err = craters.validate()
+	check(err == nil, "shape.craters", "%v", err)
+	check(shape.Erosion.Rain >= 0, "shape.erosion.rain", "must not be negative, got %g", shape.Erosion.Rain)
+	check(shape.Erosion.Capacity >= 0, "shape.erosion.capacity", "must not be negative, got %g", shape.Erosion.Capacity)
+	for _, fraction := range []struct {
+		key   string
+		value float32
+	}{
+		{"shape.erosion.evaporation", shape.Erosion.Evaporation},
+		{"shape.erosion.rate", shape.Erosion.Rate},
+		{"shape.erosion.deposition", shape.Erosion.Deposition},
+		{"shape.erosion.thermal_rate", shape.Erosion.ThermalRate},
+	} {
+		check(fraction.value >= 0 && fraction.value <= 1, fraction.key, "must be between 0 and 1, got %g", fraction.value)
+	}
+	check(shape.Erosion.Talus >= 0, "shape.erosion.talus", "must not be negative, got %g", shape.Erosion.Talus)
+	check(shape.Rivers.MinArea >= 0 && shape.Rivers.MinArea <= 1, "shape.rivers.min_area", "must be between 0 and 1, got %g", shape.Rivers.MinArea)
+	check(shape.Rivers.Depth >= 0, "shape.rivers.depth", "must not be negative, got %g", shape.Rivers.Depth)
+	check(shape.Rivers.Width >= 0, "shape.rivers.width", "must not be negative, got %g", shape.Rivers.Width)
+	check(shape.Climate.AxialTilt >= 0 && shape.Climate.AxialTilt <= 90, "shape.climate.axial_tilt", "must be between 0 and 90, got %g", shape.Climate.AxialTilt)
+	check(shape.Climate.EquatorTemperature >= shape.Climate.PoleTemperature, "shape.climate.equator_temperature", "must not be less than the pole temperature, got %g", shape.Climate.EquatorTemperature)
+	check(shape.Climate.LapseRate >= 0, "shape.climate.lapse_rate", "must not be negative, got %g", shape.Climate.LapseRate)
+	check(shape.Climate.Rainfall >= 0, "shape.climate.rainfall", "must not be negative, got %g", shape.Climate.Rainfall)
+	// Moisture fades with the distance divided by the reach
+	check(!shape.Climate.Enabled || shape.Climate.MoistureReach > 0, "shape.climate.moisture_reach", "must be greater than 0 when the climate is enabled")
+	check(shape.Climate.RainShadow >= 0, "shape.climate.rain_shadow", "must not be negative, got %g", shape.Climate.RainShadow)
+	check(shape.Ice.CapLatitude >= 0 && shape.Ice.CapLatitude <= 90, "shape.ice.cap_latitude", "must be between 0 and 90, got %g", shape.Ice.CapLatitude)
+	check(shape.Ice.Snowline >= 0, "shape.ice.snowline", "must not be negative, got %g", shape.Ice.Snowline)
+	check(shape.Ice.Roughness >= 0, "shape.ice.roughness", "must not be negative, got %g", shape.Ice.Roughness)
+	check(shape.LOD.Levels <= MaxLODLevels, "shape.lod.levels", "must be at most %d, got %d", MaxLODLevels, shape.LOD.Levels)
+
+	colors := map[string][3]float32{
+		"colors.shore_low":  f.Colors.ShoreLow,
+		"colors.shore_high": f.Colors.ShoreHigh,
+		"colors.flat_low":   f.Colors.FlatLow,
+		"colors.flat_high":  f.Colors.FlatHigh,
+		"colors.steep_low":  f.Colors.SteepLow,
+		"colors.steep_high": f.Colors.SteepHigh,
+		"colors.water":      f.Colors.Water,
+		"colors.ice":        f.Colors.Ice,
+	}
+	keys := []string{}
+	for key := range colors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		c := colors[key]
+		for i := 0; i < 3; i++ {
+			check(c[i] >= 0 && c[i] <= 1, fmt.Sprintf("%s[%d]", key, i), "must be between 0 and 1, got %g", c[i])
+		}
+	}
+
+	check(f.Texture != "", "texture", "must not be empty")
+	check(f.NormalMap != "", "normal_map", "must not be empty")
+	check(f.Shader != "", "shader", "must not be empty")
+	check(f.TextureScale >= 0, "texture_scale", "must not be negative, got %g", f.TextureScale)
+	check(f.NormalMapScale >= 0, "normal_map_scale", "must not be negative, got %g", f.NormalMapScale)
+
+	return errors.Join(errs...)
+}
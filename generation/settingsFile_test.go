@@ -0,0 +1,196 @@
+package generation
+
+import (
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"stensvad-ossianst-melvinbe-project/res"
+)
+
+func TestValidationKeys(t *testing.T) {
+	cases := []struct {
+		yaml string
+		key  string
+	}{
+		{"shape: {radius: 0}", "shape.radius"},
+		{"shape: {resolution: 0}", "shape.resolution"},
+		{"shape: {radius: 0.1, resolution: 5}", "shape.resolution"},
+		{"shape: {frequency: -1}", "shape.frequency"},
+		{"shape: {continent: {frequency: -1}}", "shape.continent.frequency"},
+		{"shape: {continent: {noise: marble}}", "shape.continent.noise"},
+		{"shape: {mountain: {frequency: -1}}", "shape.mountain.frequency"},
+		{"shape: {mountain_mask: {lacunarity: -1}}", "shape.mountain_mask"},
+		{"shape: {craters: {rim_noise: 0.5}}", "shape.craters"},
+		{"shape: {craters: {count: 3, smoothness: 0}}", "shape.craters"},
+		{"shape: {plates: {count: 3, mountain_width: 0}}", "shape.plates"},
+		{"shape: {erosion: {thermal_rate: 2}}", "shape.erosion.thermal_rate"},
+		{"shape: {climate: {axial_tilt: 91}}", "shape.climate.axial_tilt"},
+		{"shape: {ice: {cap_latitude: -1}}", "shape.ice.cap_latitude"},
+		{"shape: {lod: {levels: 17}}", "shape.lod.levels"},
+		{"colors: {water: [0, 2, 0]}", "colors.water[1]"},
+		{"texture: \"\"", "texture"},
+	}
+
+	for _, c := range cases {
+		_, err := ParsePlanetSettings("planet.yaml", []byte(c.yaml))
+		if err == nil {
+			t.Errorf("%q was accepted, want an error at %s", c.yaml, c.key)
+			continue
+		}
+		// Every error is on its own line and begins with its key
+		found := false
+		for _, line := range strings.Split(err.Error(), "\n") {
+			found = found || strings.HasPrefix(strings.TrimPrefix(line, "planet.yaml: "), c.key+":")
+		}
+		if !found {
+			t.Errorf("%q returned %q, want an error at %s", c.yaml, err, c.key)
+		}
+	}
+}
+
+func TestValidationOrder(t *testing.T) {
+	yaml := []byte(`shape:
+  continent: {noise: a}
+  mountain: {noise: b}
+  mountain_mask: {noise: c}
+  erosion: {evaporation: 2, rate: 2, deposition: 2, thermal_rate: 2}
+`)
+
+	_, first := ParsePlanetSettings("planet.yaml", yaml)
+	if first == nil {
+		t.Fatal("invalid settings were accepted")
+	}
+	for i := 0; i < 20; i++ {
+		if _, err := ParsePlanetSettings("planet.yaml", yaml); err.Error() != first.Error() {
+			t.Fatalf("errors changed order between runs:\n%v\n\n%v", first, err)
+		}
+	}
+
+	// The errors come in the order of the keys in the file
+	keys := []string{
+		"shape.continent.noise", "shape.mountain.noise", "shape.mountain_mask.noise",
+		"shape.erosion.evaporation", "shape.erosion.rate", "shape.erosion.deposition", "shape.erosion.thermal_rate",
+	}
+	at := -1
+	for _, key := range keys {
+		next := strings.Index(first.Error(), key+":")
+		if next <= at {
+			t.Errorf("%s is not reported after the keys before it:\n%v", key, first)
+		}
+		at = next
+	}
+}
+
+func TestSettingsRoundTrip(t *testing.T) {
+	for _, name := range PresetNames() {
+		for _, ext := range []string{".json", ".yaml", ".toml"} {
+			want, err := Preset(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			path := filepath.Join(t.TempDir(), name+ext)
+			if err := SavePlanetSettings(path, want); err != nil {
+				t.Fatalf("%s: %v", path, err)
+			}
+			got, err := LoadPlanetSettings(path)
+			if err != nil {
+				t.Fatalf("%s: %v", path, err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s%s changed when it was saved and loaded:\ngot  %+v\nwant %+v", name, ext, got, want)
+			}
+		}
+	}
+}
+
+func TestPresetAssets(t *testing.T) {
+	// The viewer fails to draw a planet whose texture, normal map or shader is not bundled
+	for _, name := range PresetNames() {
+		settings, err := Preset(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, path := range []string{"textures/" + settings.TexturePath, "textures/" + settings.NormalMapPath, "shaders/" + settings.ShaderPath} {
+			if _, err := fs.Stat(res.FS, path); err != nil {
+				t.Errorf("preset %s uses %s, which is not bundled", name, path)
+			}
+		}
+	}
+}
+
+func TestSettingsPresetDefaults(t *testing.T) {
+	// Keys missing from a file are taken from its preset, in every format
+	files := map[string]string{
+		"moon.json": `{"preset": "moon", "shape": {"seed": 7}}`,
+		"moon.yaml": "preset: moon\nshape:\n  seed: 7\n",
+		"moon.toml": "preset = \"moon\"\n[shape]\nseed = 7\n",
+	}
+
+	want := DefaultMoon()
+	want.Shape.Seed = 7
+	for path, data := range files {
+		got, err := ParsePlanetSettings(path, []byte(data))
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s is not the moon with seed 7", path)
+		}
+	}
+}
+
+func TestSettingsParseErrors(t *testing.T) {
+	cases := []struct {
+		path, data string
+		want       string
+	}{
+		{"planet.json", `{"shape": {"radiuss": 2}}`, "radiuss"},
+		{"planet.yaml", "shape:\n  radiuss: 2\n", "radiuss"},
+		{"planet.toml", "[shape]\nradiuss = 2\n", "radiuss"},
+		{"planet.yaml", "preset: mars\n", "unknown preset"},
+		{"planet.yaml", "shape: [1, 2\n", "planet.yaml"},
+		{"planet.ini", "", "unsupported file format"},
+	}
+
+	for _, c := range cases {
+		_, err := ParsePlanetSettings(c.path, []byte(c.data))
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s %q returned %v, want an error containing %q", c.path, c.data, err, c.want)
+		}
+	}
+}
+
+func TestBundledPlanetSettings(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "res", "planets", "*"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no planet settings found: %v", err)
+	}
+
+	for _, path := range paths {
+		want, err := LoadPlanetSettings(path)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			continue
+		}
+
+		// Settings with terrain graphs survive every format too
+		for _, ext := range []string{".json", ".yaml", ".toml"} {
+			saved := filepath.Join(t.TempDir(), "planet"+ext)
+			if err := SavePlanetSettings(saved, want); err != nil {
+				t.Fatalf("%s as %s: %v", path, ext, err)
+			}
+			got, err := LoadPlanetSettings(saved)
+			if err != nil {
+				t.Fatalf("%s as %s: %v", path, ext, err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s changed when it was saved and loaded as %s", path, ext)
+			}
+		}
+	}
+}
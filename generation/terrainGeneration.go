@@ -0,0 +1,206 @@
+package generation
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+/*
+GenTerrain generates the points of a planet as described in a given planet shape struct
+
+Parameters:
+- points: the planet points of sphere before fancy terrain generation
+- shape: the planet shape struct containing a recipe for the planets shape
+
+Returns:
+- err: an error if the terrain graph of the shape is invalid, the points are left as they were
+
+Example usage:
+
+	// Generate points of sphere first
+	points, indices := genOctahedron(100)
+	normalizePointDistances(points)
+
+	earthSettings := DefaultEarth()
+
+	err := GenTerrain(points, earthSettings.Shape)
+	// The sphere is now a planet
+*/
+func GenTerrain(points []mgl32.Vec3, shape PlanetShape) error {
+	terrain, err := CompileTerrain(shape)
+	if err != nil {
+		return err
+	}
+
+	terrain.displace(points)
+	return nil
+}
+
+/*
+GenHeights calculates the height of the terrain in every given direction, without building a mesh.
+The terrain is compiled on every call, use CompileTerrain to sample the same terrain many times.
+
+Parameters:
+- directions: points on the unit sphere to sample the terrain at
+- shape: the planet shape struct containing a recipe for the planets shape
+
+Returns:
+- heights: the distance from the center of the planet to the surface in every direction, where 1.0 is sea level
+- err: an error if the terrain graph of the shape is invalid
+
+Example usage:
+
+	heights, err := GenHeights([]mgl32.Vec3{{0, 1, 0}}, DefaultMoon().Shape)
+	northPoleRadius := heights[0] * DefaultMoon().Shape.Radius
+*/
+func GenHeights(directions []mgl32.Vec3, shape PlanetShape) ([]float32, error) {
+	terrain, err := CompileTerrain(shape)
+	if err != nil {
+		return nil, err
+	}
+
+	return terrain.Heights(directions), nil
+}
+
+// Terrain is the compiled terrain of a planet shape, with its noise, modulers and plates set up once to be sampled many times
+type Terrain struct {
+	graph     *terrainGraph
+	amplitude float32
+}
+
+/*
+CompileTerrain compiles the terrain graph of a planet shape, or its default terrain if it has none
+
+Parameters:
+- shape: the planet shape struct containing a recipe for the planets shape
+
+Returns:
+- terrain: the terrain, ready to be sampled
+- err: an error if the terrain graph of the shape is invalid
+
+Example usage:
+
+	terrain, err := CompileTerrain(DefaultEarth().Shape)
+	equator := terrain.Heights([]mgl32.Vec3{{1, 0, 0}, {0, 0, 1}, {-1, 0, 0}, {0, 0, -1}})
+	poles := terrain.Heights([]mgl32.Vec3{{0, 1, 0}, {0, -1, 0}})
+*/
+func CompileTerrain(shape PlanetShape) (*Terrain, error) {
+	// Every random decision is drawn from the planet seed so the same shape always gives the same terrain
+	rng := rand.New(rand.NewSource(shape.Seed))
+	generator := NewNoiseGenerator(rng.Int63())
+
+	nodes := shape.Terrain
+	if nodes == nil {
+		nodes = DefaultTerrain(shape)
+	}
+	graph, err := compileTerrain(nodes, &shape, generator, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Terrain{graph, shape.Amplitude}, nil
+}
+
+/*
+Heights calculates the height of the terrain in every given direction
+
+Parameters:
+- directions: points on the unit sphere to sample the terrain at
+
+Returns:
+- heights: the distance from the center of the planet to the surface in every direction, where 1.0 is sea level
+*/
+func (t *Terrain) Heights(directions []mgl32.Vec3) []float32 {
+	heights := make([]float32, len(directions))
+
+	var wg sync.WaitGroup
+
+	numGoroutines := 20 // How to parallelize
+	numPoints := len(directions)
+
+	// Amount of points to calculate per goroutine
+	concurrency := (numPoints + numGoroutines - 1) / numGoroutines
+
+	// Calculate points concurrently
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func(startIndex int) {
+			defer wg.Done()
+			endIndex := startIndex + concurrency
+			if endIndex > numPoints {
+				endIndex = numPoints
+			}
+
+			// The heights of every node of the graph at the current point
+			nodeHeights := t.nodeHeights()
+
+			for j := startIndex; j < endIndex; j++ {
+				heights[j] = t.height(directions[j], nodeHeights)
+			}
+		}(i * concurrency)
+	}
+
+	wg.Wait()
+
+	return heights
+}
+
+// Moves points on the unit sphere out to the surface of the terrain
+func (t *Terrain) displace(points []mgl32.Vec3) {
+	heights := t.Heights(points)
+
+	for i := range points {
+		points[i] = points[i].Mul(heights[i])
+	}
+}
+
+// Returns the space for the heights of every node of the graph, one is needed for every goroutine
+func (t *Terrain) nodeHeights() []float32 {
+	return make([]float32, len(t.graph.steps))
+}
+
+// Calculates the height of the terrain in one direction, where 1.0 is sea level
+func (t *Terrain) height(direction mgl32.Vec3, nodeHeights []float32) float32 {
+	// Scale the terrain by the general amplitude
+	return 1.0 + t.graph.height(direction, nodeHeights)*t.amplitude
+}
+
+// SimpleNoise calls the Snoise function with a specified amplitude and freqency
+func SimpleNoise(point mgl32.Vec3, amplitude, frequency float32) float32 {
+	x, y, z := point.X()*frequency, point.Y()*frequency, point.Z()*frequency
+	return Snoise(x, y, z) * amplitude
+}
+
+// DetailedNoise repeatadly calls the Snoise function with decreasing amplitude amplitude and increasing freqency
+func DetailedNoise(point mgl32.Vec3, amplitude, frequency float32) float32 {
+	return newFractal(FractalSettings{}, amplitude, frequency).sample(Simplex{}, point)
+}
+
+// RidgeNoise is the same as DetailedNoise but uses negative absolute values to form sharp edges
+func RidgeNoise(point mgl32.Vec3, amplitude, frequency float32) float32 {
+	return newFractal(FractalSettings{}, amplitude, frequency).ridge(Simplex{}, point)
+}
+
+// Like the min function, but smooth
+func smoothMin(a, b, k float32) float32 {
+	// Without smoothing this is the min function, avoid dividing by zero
+	if k == 0 {
+		return float32(math.Min(float64(a), float64(b)))
+	}
+
+	h := (b - a + k) / (2 * k)
+	h = float32(math.Min(math.Max(float64(h), 0.0), 1.0))
+	return a*h + b*(1-h) - k*h*(1-h)
+}
+
+// Like the max function, but smooth
+func smoothMax(a, b, k float32) float32 {
+	if k == 0 {
+		return float32(math.Max(float64(a), float64(b)))
+	}
+
+	return smoothMin(a, b, -k)
+}
@@ -0,0 +1,225 @@
+package generation
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestSmoothMinBounds(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100000; i++ {
+		a := rng.Float32()*4 - 2
+		b := rng.Float32()*4 - 2
+		k := rng.Float32()
+
+		min := float32(math.Min(float64(a), float64(b)))
+		v := smoothMin(a, b, k)
+
+		// The smooth min never rises above the min and sinks at most k/4 below it, where a and b are equal
+		if v > min+1e-6 || v < min-k/4-1e-6 {
+			t.Fatalf("smoothMin(%v, %v, %v) = %v, want a value between %v and %v", a, b, k, v, min-k/4, min)
+		}
+	}
+}
+
+func TestSmoothMaxBounds(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	for i := 0; i < 100000; i++ {
+		a := rng.Float32()*4 - 2
+		b := rng.Float32()*4 - 2
+		k := rng.Float32()
+
+		max := float32(math.Max(float64(a), float64(b)))
+		v := smoothMax(a, b, k)
+
+		if v < max-1e-6 || v > max+k/4+1e-6 {
+			t.Fatalf("smoothMax(%v, %v, %v) = %v, want a value between %v and %v", a, b, k, v, max, max+k/4)
+		}
+	}
+}
+
+func TestSmoothMinSymmetry(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 100000; i++ {
+		a := rng.Float32()*4 - 2
+		b := rng.Float32()*4 - 2
+		k := rng.Float32()
+
+		if ab, ba := smoothMin(a, b, k), smoothMin(b, a, k); math.Abs(float64(ab-ba)) > 1e-5 {
+			t.Fatalf("smoothMin(%v, %v, %v) = %v but smoothMin(%v, %v, %v) = %v", a, b, k, ab, b, a, k, ba)
+		}
+		if ab, ba := smoothMax(a, b, k), smoothMax(b, a, k); math.Abs(float64(ab-ba)) > 1e-5 {
+			t.Fatalf("smoothMax(%v, %v, %v) = %v but smoothMax(%v, %v, %v) = %v", a, b, k, ab, b, a, k, ba)
+		}
+	}
+}
+
+func TestSmoothMinWithoutSmoothing(t *testing.T) {
+	for _, c := range [][2]float32{{1, 2}, {2, 1}, {-1, -1}, {0, 0}} {
+		if v := smoothMin(c[0], c[1], 0); v != float32(math.Min(float64(c[0]), float64(c[1]))) {
+			t.Errorf("smoothMin(%v, %v, 0) = %v, want the min", c[0], c[1], v)
+		}
+		if v := smoothMax(c[0], c[1], 0); v != float32(math.Max(float64(c[0]), float64(c[1]))) {
+			t.Errorf("smoothMax(%v, %v, 0) = %v, want the max", c[0], c[1], v)
+		}
+	}
+}
+
+func TestCraterHeight(t *testing.T) {
+	shape := DefaultMoon().Shape
+	settings := CraterSettingsOf(shape)
+	crater := Crater{mgl32.Vec3{0, 0, 1}, 0.2}
+	craters := []Crater{crater}
+
+	// The center of a crater is lowered to the crater floor
+	if h := newCraterField(&settings, Simplex{}, craters).height(crater.Position); h >= 0 {
+		t.Errorf("height at the center of a crater is %v, want a negative height", h)
+	}
+
+	// Far away from the crater the terrain is left alone
+	if h := newCraterField(&settings, Simplex{}, craters).height(mgl32.Vec3{0, 0, -1}); h != 0 {
+		t.Errorf("height on the other side of the planet from a crater is %v, want 0", h)
+	}
+
+	// Without craters nothing changes
+	if h := newCraterField(&settings, Simplex{}, nil).height(crater.Position); h != 0 {
+		t.Errorf("height without craters is %v, want 0", h)
+	}
+}
+
+// Calculates the heights of a shape whose terrain graph is valid
+func genHeights(t *testing.T, directions []mgl32.Vec3, shape PlanetShape) []float32 {
+	t.Helper()
+
+	heights, err := GenHeights(directions, shape)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return heights
+}
+
+func TestGenHeightsDeterministic(t *testing.T) {
+	directions := make([]mgl32.Vec3, 1000)
+	rng := rand.New(rand.NewSource(4))
+	for i := range directions {
+		directions[i] = randomPoint(rng, 1).Normalize()
+	}
+
+	for _, shape := range []PlanetShape{DefaultEarth().Shape, DefaultMoon().Shape} {
+		a := genHeights(t, directions, shape)
+		b := genHeights(t, directions, shape)
+		for i := range a {
+			if a[i] != b[i] {
+				t.Fatalf("seed %d gave the height %v and then %v at %v", shape.Seed, a[i], b[i], directions[i])
+			}
+		}
+
+		// A different seed gives a different planet
+		shape.Seed++
+		c := genHeights(t, directions, shape)
+		same := 0
+		for i := range a {
+			if a[i] == c[i] {
+				same++
+			}
+		}
+		if same == len(a) {
+			t.Errorf("seeds %d and %d gave the same heights", shape.Seed-1, shape.Seed)
+		}
+	}
+}
+
+func TestCompileTerrain(t *testing.T) {
+	directions := make([]mgl32.Vec3, 100)
+	rng := rand.New(rand.NewSource(6))
+	for i := range directions {
+		directions[i] = randomPoint(rng, 1).Normalize()
+	}
+
+	// A compiled terrain is sampled like GenHeights, however many times
+	shape := DefaultEarth().Shape
+	terrain, err := CompileTerrain(shape)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := genHeights(t, directions, shape)
+	for run := 0; run < 2; run++ {
+		for i, h := range terrain.Heights(directions) {
+			if h != want[i] {
+				t.Fatalf("run %d: height %d is %v, want %v", run, i, h, want[i])
+			}
+		}
+	}
+}
+
+func TestInvalidTerrain(t *testing.T) {
+	shape := DefaultEarth().Shape
+	shape.Terrain = []TerrainNode{{Name: "a", Type: "plateau"}}
+
+	if _, err := CompileTerrain(shape); err == nil || !strings.Contains(err.Error(), "plateau") {
+		t.Errorf("CompileTerrain returned %v, want an error about the plateau node", err)
+	}
+	if _, err := GenHeights([]mgl32.Vec3{{0, 1, 0}}, shape); err == nil {
+		t.Error("GenHeights accepted an invalid terrain")
+	}
+
+	// The points are left on the sphere
+	points := []mgl32.Vec3{{0, 1, 0}, {1, 0, 0}}
+	if err := GenTerrain(points, shape); err == nil {
+		t.Error("GenTerrain accepted an invalid terrain")
+	}
+	if points[0] != (mgl32.Vec3{0, 1, 0}) || points[1] != (mgl32.Vec3{1, 0, 0}) {
+		t.Errorf("GenTerrain moved the points to %v", points)
+	}
+
+	shape.Res = 4
+	if _, _, err := GenPlanet(shape); err == nil {
+		t.Error("GenPlanet accepted an invalid terrain")
+	}
+}
+
+func TestGenCratersOnSphere(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+
+	settings := CraterSettings{Count: 1000, MaxRadius: 0.25, SizeExponent: 0.5}
+	for _, crater := range genCraters(&settings, rng) {
+		if l := crater.Position.Len(); math.Abs(float64(l-1)) > 1e-5 {
+			t.Fatalf("crater at %v is %v from the center, want 1", crater.Position, l)
+		}
+		if crater.Radius < 0 || crater.Radius > 0.25 {
+			t.Fatalf("crater radius is %v, want a radius between 0 and 0.25", crater.Radius)
+		}
+	}
+}
+
+func BenchmarkGenTerrain(b *testing.B) {
+	for _, preset := range []string{"earth", "moon"} {
+		b.Run(preset, func(b *testing.B) {
+			settings, _ := Preset(preset)
+			sphere, _ := genOctahedron(100)
+			normalizePointDistances(sphere)
+			points := make([]mgl32.Vec3, len(sphere))
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				copy(points, sphere)
+				if err := GenTerrain(points, settings.Shape); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSnoise(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Snoise(float32(i)*0.01, 1.5, 2.5)
+	}
+}
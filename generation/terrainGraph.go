@@ -0,0 +1,348 @@
+package generation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// TerrainNodeType names what a terrain node does with its inputs
+type TerrainNodeType string
+
+const (
+	ConstantNode TerrainNodeType = "constant" // returns its value everywhere
+	NoiseNode    TerrainNodeType = "noise"    // a single sample of noise
+	FbmNode      TerrainNodeType = "fbm"      // octaves of noise with rising frequency and falling amplitude, like DetailedNoise
+	RidgeNode    TerrainNodeType = "ridge"    // sharp ridges from octaves of noise, like RidgeNoise
+	AddNode      TerrainNodeType = "add"      // the sum of its inputs and its value
+	MultiplyNode TerrainNodeType = "multiply" // the product of its inputs
+	MinNode      TerrainNodeType = "min"      // the smooth min of its inputs
+	MaxNode      TerrainNodeType = "max"      // the smooth max of its inputs
+	MaskNode     TerrainNodeType = "mask"     // its first input, kept below its second input raised above zero
+	CurveNode    TerrainNodeType = "curve"    // its input remapped through a curve of points
+	CraterNode   TerrainNodeType = "craters"  // a field of randomly placed craters
+	PlateNode    TerrainNodeType = "plates"   // land and sea floor from tectonic plates, with mountains and rifts at their boundaries
+)
+
+// TerrainNodeTypes lists every terrain node type in the order they are documented
+var TerrainNodeTypes = []TerrainNodeType{
+	ConstantNode, NoiseNode, FbmNode, RidgeNode, AddNode, MultiplyNode, MinNode, MaxNode, MaskNode, CurveNode, CraterNode, PlateNode,
+}
+
+// TerrainNode is one step of a terrain graph. Every node calculates a height from the heights of the nodes
+// it names as inputs, which have to come before it in the graph. Fields a node type does not use are ignored.
+type TerrainNode struct {
+	Name   string
+	Type   TerrainNodeType
+	Inputs []string
+
+	// Noise, fbm and ridge:
+	Noise     NoiseSettings   // also bends the boundaries of plates
+	Fractal   FractalSettings // fbm and ridge only
+	Amplitude float32         // also scales the height of craters and plates
+	Frequency float32         // scaled by the frequency of the planet shape
+
+	Value      float32      // constant and add
+	Smoothness float32      // min, max and mask
+	Points     [][2]float32 // curve, sorted by input height
+
+	Craters CraterSettings // craters only
+	Plates  PlateSettings  // plates only
+}
+
+// A terrain graph that is ready to calculate heights
+type terrainGraph struct {
+	steps []terrainStep
+}
+
+// Calculates the height of a node from the heights of every node before it
+type terrainStep func(point mgl32.Vec3, heights []float32) float32
+
+/*
+DefaultTerrain builds the terrain graph that is used when a planet shape has no graph of its own,
+from the ocean, continent, plate, mountain and crater settings of the shape
+
+Parameters:
+- shape: the planet shape to take the settings of the terrain layers from
+
+Returns:
+- nodes: continents with deepened oceans, masked ridge mountains and craters, with continents from tectonic plates if the shape has any
+
+Example usage:
+
+	shape := DefaultEarth().Shape
+	shape.Terrain = DefaultTerrain(shape)
+	shape.Terrain[0].Noise = NoiseSettings{PerlinNoise, 0.0}
+*/
+func DefaultTerrain(shape PlanetShape) []TerrainNode {
+	// Generate the general bumpyness of the planet surface and locations of the oceans
+	continents := []TerrainNode{
+		{Name: "continents", Type: FbmNode, Noise: shape.ContinentNoise, Fractal: shape.ContinentFractal, Amplitude: shape.ContinentAmplitude, Frequency: shape.ContinentFrequency},
+	}
+	if shape.PlateCount > 0 {
+		// Let the plates decide where the oceans are, and keep the noise for the coasts and the bumpyness
+		continents = []TerrainNode{
+			{Name: "plates", Type: PlateNode, Noise: shape.ContinentNoise, Amplitude: shape.ContinentAmplitude, Frequency: shape.ContinentFrequency, Plates: PlateSettingsOf(shape)},
+			{Name: "coasts", Type: FbmNode, Noise: shape.ContinentNoise, Fractal: shape.ContinentFractal, Amplitude: shape.ContinentAmplitude * 0.75, Frequency: shape.ContinentFrequency},
+			{Name: "continents", Type: AddNode, Inputs: []string{"plates", "coasts"}},
+		}
+	}
+
+	return append(continents, []TerrainNode{
+		// Deepen the deep areas of the surface to form oceans
+		{Name: "oceans", Type: CurveNode, Inputs: []string{"continents"}, Points: [][2]float32{{-1, -shape.OceanDepth}, {0, 0}, {1, 1}}},
+		// Raise the deepest areas to to ocean floor
+		{Name: "ocean floor", Type: ConstantNode, Value: -shape.OceanFloorDepth},
+		{Name: "ground", Type: MaxNode, Inputs: []string{"oceans", "ocean floor"}, Smoothness: shape.OceanSmoothness},
+
+		// Generate a mask for the mountains to keep some areas free from mountains
+		{Name: "mountain mask", Type: FbmNode, Noise: shape.MountainMaskNoise, Fractal: shape.MountainMaskFractal, Amplitude: shape.MountainMaskAmplitude, Frequency: shape.MountainFrequency * 1.1},
+		{Name: "mountain mask offset", Type: AddNode, Inputs: []string{"mountain mask"}, Value: shape.MountainMaskOffset},
+		// Generate the actual mountains
+		{Name: "ridges", Type: RidgeNode, Noise: shape.MountainNoise, Fractal: shape.MountainFractal, Amplitude: shape.MountainAmplitude, Frequency: shape.MountainFrequency},
+		{Name: "zero", Type: ConstantNode},
+		{Name: "mountain bases", Type: MaxNode, Inputs: []string{"ridges", "zero"}, Smoothness: shape.MountainSmoothness},
+		// Limit the mountains to stay within the mask
+		{Name: "mountains", Type: MaskNode, Inputs: []string{"mountain bases", "mountain mask offset"}, Smoothness: shape.MountainMaskSmoothness},
+
+		{Name: "land", Type: AddNode, Inputs: []string{"ground", "mountains"}},
+		{Name: "third", Type: ConstantNode, Value: 1.0 / 3.0},
+		{Name: "flattened land", Type: MultiplyNode, Inputs: []string{"land", "third"}},
+
+		// Add craters
+		{Name: "craters", Type: CraterNode, Amplitude: shape.Amplitude, Craters: CraterSettingsOf(shape)},
+		{Name: "height", Type: AddNode, Inputs: []string{"flattened land", "craters"}},
+	}...)
+}
+
+// Checks that every node of a terrain graph can be calculated, the errors name the node they were found at
+func validateTerrain(nodes []TerrainNode) error {
+	_, err := compileTerrain(nodes, &PlanetShape{}, nil, rand.New(rand.NewSource(0)))
+	return err
+}
+
+// Turns the nodes of a terrain graph into steps, drawing craters from rng in the order of the nodes
+func compileTerrain(nodes []TerrainNode, shape *PlanetShape, generator *NoiseGenerator, rng *rand.Rand) (*terrainGraph, error) {
+	if len(nodes) == 0 {
+		return nil, errors.New("terrain has no nodes")
+	}
+
+	graph := &terrainGraph{make([]terrainStep, len(nodes))}
+	indices := map[string]int{}
+
+	for i := range nodes {
+		node := &nodes[i]
+
+		fail := func(format string, args ...interface{}) error {
+			name := node.Name
+			if name == "" {
+				name = fmt.Sprint(i)
+			}
+			return fmt.Errorf("terrain node %q: %s", name, fmt.Sprintf(format, args...))
+		}
+
+		// Inputs can only be nodes that come before, so the graph never loops
+		inputs := make([]int, len(node.Inputs))
+		for j, input := range node.Inputs {
+			index, ok := indices[input]
+			if !ok {
+				return nil, fail("input %q is not the name of an earlier node", input)
+			}
+			inputs[j] = index
+		}
+
+		if node.Name != "" {
+			if _, ok := indices[node.Name]; ok {
+				return nil, fail("there is already a node with this name")
+			}
+			indices[node.Name] = i
+		}
+
+		step, err := compileTerrainNode(node, inputs, shape, generator, rng)
+		if err != nil {
+			return nil, fail("%v", err)
+		}
+		graph.steps[i] = step
+	}
+
+	return graph, nil
+}
+
+// Turns a single node into a step that reads the heights of its inputs
+func compileTerrainNode(node *TerrainNode, inputs []int, shape *PlanetShape, generator *NoiseGenerator, rng *rand.Rand) (terrainStep, error) {
+	wantInputs := func(min, max int) error {
+		if len(inputs) < min || len(inputs) > max {
+			if min == max {
+				return fmt.Errorf("%s nodes take %d inputs, got %d", node.Type, min, len(inputs))
+			}
+			return fmt.Errorf("%s nodes take at least %d inputs, got %d", node.Type, min, len(inputs))
+		}
+		return nil
+	}
+	const many = math.MaxInt32
+
+	amplitude := node.Amplitude
+	frequency := node.Frequency * shape.Frequency
+
+	switch node.Type {
+	case ConstantNode:
+		value := node.Value
+		return func(point mgl32.Vec3, heights []float32) float32 {
+			return value
+		}, wantInputs(0, 0)
+
+	case NoiseNode, FbmNode, RidgeNode:
+		if err := wantInputs(0, 0); err != nil {
+			return nil, err
+		}
+		noise, err := node.Noise.Noise(generator)
+		if err != nil {
+			return nil, err
+		}
+
+		if node.Type == NoiseNode {
+			return func(point mgl32.Vec3, heights []float32) float32 {
+				return noise.Sample(point.X()*frequency, point.Y()*frequency, point.Z()*frequency) * amplitude
+			}, nil
+		}
+
+		if err := node.Fractal.validate(); err != nil {
+			return nil, err
+		}
+		octaves := newFractal(node.Fractal, amplitude, frequency)
+
+		if node.Type == FbmNode {
+			return func(point mgl32.Vec3, heights []float32) float32 {
+				return octaves.sample(noise, point)
+			}, nil
+		}
+		return func(point mgl32.Vec3, heights []float32) float32 {
+			return octaves.ridge(noise, point)
+		}, nil
+
+	case AddNode:
+		value := node.Value
+		return func(point mgl32.Vec3, heights []float32) float32 {
+			sum := value
+			for _, input := range inputs {
+				sum += heights[input]
+			}
+			return sum
+		}, wantInputs(1, many)
+
+	case MultiplyNode:
+		return func(point mgl32.Vec3, heights []float32) float32 {
+			product := heights[inputs[0]]
+			for _, input := range inputs[1:] {
+				product *= heights[input]
+			}
+			return product
+		}, wantInputs(1, many)
+
+	case MinNode, MaxNode:
+		k := node.Smoothness
+		if k < 0 {
+			return nil, fmt.Errorf("smoothness must not be negative, got %g", k)
+		}
+		smooth := smoothMin
+		if node.Type == MaxNode {
+			smooth = smoothMax
+		}
+		return func(point mgl32.Vec3, heights []float32) float32 {
+			height := heights[inputs[0]]
+			for _, input := range inputs[1:] {
+				height = smooth(height, heights[input], k)
+			}
+			return height
+		}, wantInputs(1, many)
+
+	case MaskNode:
+		k := node.Smoothness
+		if k < 0 {
+			return nil, fmt.Errorf("smoothness must not be negative, got %g", k)
+		}
+		return func(point mgl32.Vec3, heights []float32) float32 {
+			mask := smoothMax(1e-6, heights[inputs[1]], k)
+			return smoothMin(mask, heights[inputs[0]], 0)
+		}, wantInputs(2, 2)
+
+	case CurveNode:
+		if err := wantInputs(1, 1); err != nil {
+			return nil, err
+		}
+		points := node.Points
+		if len(points) < 2 {
+			return nil, fmt.Errorf("curves need at least 2 points, got %d", len(points))
+		}
+		if !sort.SliceIsSorted(points, func(i, j int) bool { return points[i][0] < points[j][0] }) {
+			return nil, errors.New("curve points must be sorted by their first value")
+		}
+		for i := 1; i < len(points); i++ {
+			if points[i][0] == points[i-1][0] {
+				return nil, fmt.Errorf("curve has two points at %g", points[i][0])
+			}
+		}
+		return func(point mgl32.Vec3, heights []float32) float32 {
+			return evalCurve(points, heights[inputs[0]])
+		}, nil
+
+	case CraterNode:
+		if err := wantInputs(0, 0); err != nil {
+			return nil, err
+		}
+		settings := node.Craters
+		if err := settings.validate(); err != nil {
+			return nil, err
+		}
+		field := newCraterField(&settings, Simplex{generator}, genCraters(&settings, rng))
+		return func(point mgl32.Vec3, heights []float32) float32 {
+			return field.height(point) * amplitude
+		}, nil
+
+	case PlateNode:
+		if err := wantInputs(0, 0); err != nil {
+			return nil, err
+		}
+		settings := node.Plates
+		if err := settings.validate(); err != nil {
+			return nil, err
+		}
+		noise, err := node.Noise.Noise(generator)
+		if err != nil {
+			return nil, err
+		}
+		field := &plateField{&settings, genPlates(&settings, rng), noise, frequency}
+		return func(point mgl32.Vec3, heights []float32) float32 {
+			return field.height(point) * amplitude
+		}, nil
+
+	case "":
+		return nil, errors.New("type is missing")
+	}
+
+	return nil, fmt.Errorf("unknown type %q, expected one of %v", node.Type, TerrainNodeTypes)
+}
+
+// Calculates the height of every node at a point, heights has room for one height per node.
+// Returns the height of the last node.
+func (g *terrainGraph) height(point mgl32.Vec3, heights []float32) float32 {
+	for i, step := range g.steps {
+		heights[i] = step(point, heights)
+	}
+	return heights[len(heights)-1]
+}
+
+// Linearly interpolates between the points of a curve, and continues the first and last segments past the ends
+func evalCurve(points [][2]float32, x float32) float32 {
+	i := sort.Search(len(points)-1, func(i int) bool { return points[i+1][0] >= x })
+	if i == len(points)-1 {
+		i--
+	}
+	a, b := points[i], points[i+1]
+	t := (x - a[0]) / (b[0] - a[0])
+	return a[1] + (b[1]-a[1])*t
+}
@@ -0,0 +1,127 @@
+package generation
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestEvalCurve(t *testing.T) {
+	points := [][2]float32{{-1, -7}, {0, 0}, {1, 1}}
+
+	for _, c := range [][2]float32{
+		{-1, -7}, {-0.5, -3.5}, {0, 0}, {0.25, 0.25}, {1, 1},
+		// Past the ends the first and last segments continue
+		{-2, -14}, {3, 3},
+	} {
+		if v := evalCurve(points, c[0]); math.Abs(float64(v-c[1])) > 1e-5 {
+			t.Errorf("curve at %v is %v, want %v", c[0], v, c[1])
+		}
+	}
+}
+
+func TestTerrainGraph(t *testing.T) {
+	shape := DefaultMoon().Shape
+	shape.Amplitude = 0.5
+	shape.Terrain = []TerrainNode{
+		{Name: "a", Type: ConstantNode, Value: 0.2},
+		{Name: "b", Type: ConstantNode, Value: 0.3},
+		{Name: "sum", Type: AddNode, Inputs: []string{"a", "b"}, Value: 0.1},
+		{Name: "product", Type: MultiplyNode, Inputs: []string{"sum", "b"}},
+		{Name: "min", Type: MinNode, Inputs: []string{"product", "a"}},
+	}
+
+	// min(((0.2 + 0.3 + 0.1) * 0.3), 0.2) = 0.18, scaled by the amplitude
+	for _, h := range genHeights(t, []mgl32.Vec3{{0, 1, 0}, {1, 0, 0}}, shape) {
+		if math.Abs(float64(h-1.09)) > 1e-5 {
+			t.Errorf("height is %v, want 1.09", h)
+		}
+	}
+}
+
+func TestTerrainGraphErrors(t *testing.T) {
+	for _, c := range []struct {
+		nodes []TerrainNode
+		want  string
+	}{
+		{nil, "no nodes"},
+		{[]TerrainNode{{Name: "a", Type: "plateau"}}, `unknown type "plateau"`},
+		{[]TerrainNode{{Name: "a"}}, "type is missing"},
+		{[]TerrainNode{{Name: "a", Type: AddNode, Inputs: []string{"b"}}, {Name: "b", Type: ConstantNode}}, `input "b" is not the name of an earlier node`},
+		{[]TerrainNode{{Name: "a", Type: AddNode, Inputs: []string{"a"}}}, `input "a" is not the name of an earlier node`},
+		{[]TerrainNode{{Name: "a", Type: ConstantNode}, {Name: "a", Type: ConstantNode}}, "already a node with this name"},
+		{[]TerrainNode{{Name: "a", Type: ConstantNode}, {Name: "b", Type: MaskNode, Inputs: []string{"a"}}}, "take 2 inputs, got 1"},
+		{[]TerrainNode{{Name: "a", Type: MultiplyNode}}, "at least 1 inputs, got 0"},
+		{[]TerrainNode{{Name: "a", Type: FbmNode, Noise: NoiseSettings{"plaid", 0}}}, `unknown noise "plaid"`},
+		{[]TerrainNode{{Name: "a", Type: ConstantNode}, {Name: "b", Type: CurveNode, Inputs: []string{"a"}, Points: [][2]float32{{1, 0}, {0, 1}}}}, "sorted"},
+		{[]TerrainNode{{Name: "a", Type: CraterNode, Craters: CraterSettings{Count: 3, MaxRadius: 0.1}}}, "smoothness must be greater than 0"},
+	} {
+		err := validateTerrain(c.nodes)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("validateTerrain(%v) returned %v, want an error containing %q", c.nodes, err, c.want)
+		}
+	}
+
+	if err := validateTerrain(DefaultTerrain(DefaultEarth().Shape)); err != nil {
+		t.Errorf("the default terrain is invalid: %v", err)
+	}
+}
+
+func TestDefaultTerrainMatchesLayers(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	directions := make([]mgl32.Vec3, 1000)
+	for i := range directions {
+		directions[i] = randomPoint(rng, 1).Normalize()
+	}
+
+	// A shape without a graph is generated with the default graph
+	shape := DefaultMoon().Shape
+	a := genHeights(t, directions, shape)
+	shape.Terrain = DefaultTerrain(shape)
+	b := genHeights(t, directions, shape)
+
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("height at %v is %v without a graph and %v with the default graph", directions[i], a[i], b[i])
+		}
+	}
+}
+
+func TestTerrainSettingsFile(t *testing.T) {
+	data := []byte(`
+shape:
+  amplitude: 1.0
+  terrain:
+    - name: hills
+      type: fbm
+      noise: worley
+      amplitude: 0.2
+      frequency: 2.0
+    - name: terraces
+      type: curve
+      inputs: [hills]
+      points: [[-1, -1], [0, 0.1], [1, 0.2]]
+`)
+
+	settings, err := ParsePlanetSettings("terraces.yaml", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(settings.Shape.Terrain) != 2 || settings.Shape.Terrain[0].Noise.Type != WorleyNoise {
+		t.Fatalf("terrain is %+v, want worley hills and terraces", settings.Shape.Terrain)
+	}
+
+	for _, h := range genHeights(t, []mgl32.Vec3{{0, 1, 0}, {0, 0, 1}}, settings.Shape) {
+		if h < 1-1.0 || h > 1+0.2 {
+			t.Errorf("height is %v, want a height on the curve", h)
+		}
+	}
+
+	broken := strings.Replace(string(data), "inputs: [hills]", "inputs: [valleys]", 1)
+	if _, err := ParsePlanetSettings("terraces.yaml", []byte(broken)); err == nil || !strings.Contains(err.Error(), "shape.terrain") {
+		t.Errorf("a graph with an unknown input returned %v, want an error at shape.terrain", err)
+	}
+}
@@ -3,9 +3,11 @@ module stensvad-ossianst-melvinbe-project
 go 1.20
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/go-gl/gl v0.0.0-20211210172815-726fda9656d6
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20221017161538-93cebf72946b
 	github.com/go-gl/mathgl v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require golang.org/x/image v0.0.0-20210220032944-ac19c3e999fb // indirect
@@ -0,0 +1,82 @@
+// Package fileformat reads and writes the JSON, YAML and TOML files used for settings and scenes.
+package fileformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Decode decodes data into v, using the format given by the extension of path.
+// Unknown keys are reported as errors if strict is set.
+func Decode(path string, data []byte, v interface{}, strict bool) error {
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		if strict {
+			decoder.DisallowUnknownFields()
+		}
+		err = decoder.Decode(v)
+
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(strict)
+		err = decoder.Decode(v)
+		// An empty document leaves every value at its default
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+
+	case ".toml":
+		var meta toml.MetaData
+		meta, err = toml.Decode(string(data), v)
+		if err == nil && strict {
+			if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+				err = fmt.Errorf("unknown key %q", undecoded[0].String())
+			}
+		}
+
+	default:
+		return fmt.Errorf("%s: unsupported file format %q, expected .json, .yaml, .yml or .toml", path, filepath.Ext(path))
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	return nil
+}
+
+// Encode encodes v in the format given by the extension of path
+func Encode(path string, v interface{}) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		return append(data, '\n'), err
+
+	case ".yaml", ".yml":
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(v); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), encoder.Close()
+
+	case ".toml":
+		var buf bytes.Buffer
+		err := toml.NewEncoder(&buf).Encode(v)
+		return buf.Bytes(), err
+	}
+
+	return nil, fmt.Errorf("%s: unsupported file format %q, expected .json, .yaml, .yml or .toml", path, filepath.Ext(path))
+}
@@ -0,0 +1,61 @@
+package fileformat
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testDocument struct {
+	Name   string    `json:"name" yaml:"name" toml:"name"`
+	Values []float32 `json:"values" yaml:"values" toml:"values"`
+	Nested struct {
+		Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	} `json:"nested" yaml:"nested" toml:"nested"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	want := testDocument{Name: "planet", Values: []float32{0.5, 1, 2}}
+	want.Nested.Enabled = true
+
+	for _, path := range []string{"a.json", "a.yaml", "a.yml", "a.toml"} {
+		data, err := Encode(path, want)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		var got testDocument
+		if err := Decode(path, data, &got, true); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: decoded %+v, want %+v", path, got, want)
+		}
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	files := map[string]string{
+		"a.json": `{"name": "planet", "colour": 1}`,
+		"a.yaml": "name: planet\ncolour: 1\n",
+		"a.toml": "name = \"planet\"\ncolour = 1\n",
+	}
+
+	for path, data := range files {
+		var doc testDocument
+		if err := Decode(path, []byte(data), &doc, true); err == nil {
+			t.Errorf("%s: unknown key was accepted in strict mode", path)
+		}
+		if err := Decode(path, []byte(data), &doc, false); err != nil || doc.Name != "planet" {
+			t.Errorf("%s: decoded %q and %v without strict mode", path, doc.Name, err)
+		}
+	}
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	var doc testDocument
+	if err := Decode("a.ini", nil, &doc, false); err == nil {
+		t.Errorf("decoding .ini did not fail")
+	}
+	if _, err := Encode("a.ini", doc); err == nil {
+		t.Errorf("encoding .ini did not fail")
+	}
+}
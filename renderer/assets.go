@@ -0,0 +1,11 @@
+package renderer
+
+import (
+	"io/fs"
+
+	"stensvad-ossianst-melvinbe-project/res"
+)
+
+// Assets is where shaders ("shaders" folder) and textures ("textures" folder) are loaded from.
+// Defaults to the assets bundled into the binary, use res.NewSearchPath to look in other folders first.
+var Assets fs.FS = res.FS
@@ -1,4 +1,4 @@
-package main
+package renderer
 
 import (
 	"unsafe"
@@ -26,11 +26,32 @@ Parameters:
 - w: the width of the frame buffer
 - h: the height of the frame buffer
 - shaderPath: the file name of the associated shader program
+- cam: the camera the frame is seen through
 
 Returns:
 - ppf: a PostProcessingFrame object
 */
-func NewPostProcessingFrame(w uint32, h uint32, shaderPath string) PostProcessingFrame {
+func NewPostProcessingFrame(w uint32, h uint32, shaderPath string, cam *Camera) PostProcessingFrame {
+	ppf, err := LoadPostProcessingFrame(w, h, shaderPath, cam)
+	if err != nil {
+		panic(err)
+	}
+
+	return ppf
+}
+
+// LoadPostProcessingFrame is like NewPostProcessingFrame but returns an error if the shader or textures could not be loaded
+func LoadPostProcessingFrame(w uint32, h uint32, shaderPath string, cam *Camera) (PostProcessingFrame, error) {
+	shader, err := LoadShader(shaderPath)
+	if err != nil {
+		return PostProcessingFrame{}, err
+	}
+
+	normalMap, err := LoadTexture("normalmap_ocean.png")
+	if err != nil {
+		return PostProcessingFrame{}, err
+	}
+
 	// Vertices and indices for the postprocessing rectangle
 	var vertices = []float32{
 		1.0, 1.0, 1.0, 1.0,
@@ -47,30 +68,28 @@ func NewPostProcessingFrame(w uint32, h uint32, shaderPath string) PostProcessin
 	// Create VAO and VBO for rectangle covering the screen
 	vb := NewVertexBuffer(vertices)
 	ib := NewIndexBuffer(indices)
-	vb.bind()
+	vb.Bind()
 
 	va := NewVertexArray([]int{2, 2})
-	va.bind()
-	shader := NewShader(shaderPath)
+	va.Bind()
 
-	normalMap := NewTexture("normalmap_ocean.png")
-	normalMap.bind(5)
+	normalMap.Bind(5)
 
 	// Create framebuffer
 	fb := NewFrameBuffer(w, h)
-	fb.addColorTexture(2, w, h, gl.COLOR_ATTACHMENT0, gl.RGBA)
-	fb.addColorTexture(3, w, h, gl.COLOR_ATTACHMENT1, gl.RGBA32F)
-	fb.addDepthTexture(10, w, h)
+	fb.AddColorTexture(2, w, h, gl.COLOR_ATTACHMENT0, gl.RGBA)
+	fb.AddColorTexture(3, w, h, gl.COLOR_ATTACHMENT1, gl.RGBA32F)
+	fb.AddDepthTexture(10, w, h)
 
 	ppf := PostProcessingFrame{va, fb, ib, []uint32{}, shader, normalMap}
-	ppf.shader.bind()
-	ppf.shader.setUniform1i("colorTexture", 2)
-	ppf.shader.setUniform1i("depthTexture", 3)
-	ppf.shader.setUniform1f("camNear", cam.GetNearPlane())
-	ppf.shader.setUniform1f("camFar", cam.GetFarPlane())
-	ppf.shader.setUniform1i("oceanNormalMap", 5)
-
-	return ppf
+	ppf.shader.Bind()
+	ppf.shader.SetUniform1i("colorTexture", 2)
+	ppf.shader.SetUniform1i("depthTexture", 3)
+	ppf.shader.SetUniform1f("camNear", cam.GetNearPlane())
+	ppf.shader.SetUniform1f("camFar", cam.GetFarPlane())
+	ppf.shader.SetUniform1i("oceanNormalMap", 5)
+
+	return ppf, nil
 }
 
 /*
@@ -81,13 +100,15 @@ Parameters:
 - vectors: vec4 values to add as buffer data
 - size: the amount of memory to allocate for the buffer data
 */
-func (ppf *PostProcessingFrame) addUniformBufferVec4(block string, vectors []mgl32.Vec4, size int) {
+func (ppf *PostProcessingFrame) AddUniformBufferVec4(block string, vectors []mgl32.Vec4, size int) {
 	var id uint32
 	gl.GenBuffers(1, &id)
 	gl.BindBuffer(gl.UNIFORM_BUFFER, id)
 
 	gl.BufferData(gl.UNIFORM_BUFFER, size, nil, gl.DYNAMIC_DRAW)
-	gl.BufferSubData(gl.UNIFORM_BUFFER, 0, int(unsafe.Sizeof(mgl32.Vec4{}))*len(vectors), unsafe.Pointer(&vectors[0]))
+	if len(vectors) > 0 {
+		gl.BufferSubData(gl.UNIFORM_BUFFER, 0, int(unsafe.Sizeof(mgl32.Vec4{}))*len(vectors), unsafe.Pointer(&vectors[0]))
+	}
 
 	blockId := gl.GetUniformBlockIndex(ppf.shader.id, gl.Str(block+"\x00"))
 
@@ -104,19 +125,22 @@ Parameters:
 - ub: the id of the uniform buffer to update
 - vectors: the updated vec4 values
 */
-func (ppf *PostProcessingFrame) updateUniformBufferVec4(ub uint32, vectors []mgl32.Vec4) {
+func (ppf *PostProcessingFrame) UpdateUniformBufferVec4(ub uint32, vectors []mgl32.Vec4) {
+	if len(vectors) == 0 {
+		return
+	}
 	gl.BindBuffer(gl.UNIFORM_BUFFER, ub)
 	gl.BufferSubData(gl.UNIFORM_BUFFER, 0, int(unsafe.Sizeof(mgl32.Vec4{}))*len(vectors), unsafe.Pointer(&vectors[0]))
 }
 
 // Bind necessary buffers and shader programs and render the post processing effects
-func (ppf *PostProcessingFrame) draw() {
-	ppf.shader.bind()
-	ppf.va.bind()
-	ppf.ib.bind()
+func (ppf *PostProcessingFrame) Draw() {
+	ppf.shader.Bind()
+	ppf.va.Bind()
+	ppf.ib.Bind()
 
 	gl.DrawElements(gl.TRIANGLES, ppf.ib.count, gl.UNSIGNED_INT, gl.PtrOffset(0))
 
-	ppf.shader.unbind()
-	ppf.va.unbind()
+	ppf.shader.Unbind()
+	ppf.va.Unbind()
 }
@@ -0,0 +1,56 @@
+package renderer
+
+import (
+	"image"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Configures the global OpenGL state the renderer expects, needs an OpenGL context
+func ConfigureGL() {
+	gl.Enable(gl.DEPTH_TEST)
+	gl.Enable(gl.CULL_FACE)
+	gl.DepthFunc(gl.LESS)
+	gl.ClearColor(0.34, 0.32, 0.45, 1.0)
+}
+
+/*
+ReadPixels reads what has been drawn to the default framebuffer, for example to save a screenshot
+or to render without showing a window
+
+Parameters:
+- width: the width of the framebuffer
+- height: the height of the framebuffer
+
+Returns:
+- img: the pixels of the framebuffer, with the first row at the top
+
+Example usage:
+
+	system.Draw(&cam)
+	img := ReadPixels(fbWidth, fbHeight)
+*/
+func ReadPixels(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
+	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
+	gl.ReadPixels(0, 0, int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+
+	// OpenGL stores the bottom row first
+	row := make([]uint8, img.Stride)
+	for y := 0; y < height/2; y++ {
+		top := img.Pix[y*img.Stride : (y+1)*img.Stride]
+		bottom := img.Pix[(height-1-y)*img.Stride : (height-y)*img.Stride]
+		copy(row, top)
+		copy(top, bottom)
+		copy(bottom, row)
+	}
+
+	// The alpha channel is not used by the renderer
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+
+	return img
+}
@@ -1,10 +1,10 @@
-package main
+package renderer
 
 import (
 	"fmt"
 	"image"
 	"image/draw"
-	"os"
+	"path"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 )
@@ -15,39 +15,41 @@ type CubemapTexture struct {
 
 // Creates a new cubemap texture and returns it. Panics if something goes wrong while loading the texture.
 func NewCubemapTexture(filePath string) CubemapTexture {
-	id, err := genCubemapTexture(filePath)
+	texture, err := LoadCubemapTexture(filePath)
 	if err != nil {
 		panic(err)
 	}
 
-	return CubemapTexture{id}
+	return texture
+}
+
+// LoadCubemapTexture creates a new cubemap texture from a folder of six faces in the "textures" folder,
+// or returns an error naming the face that could not be loaded
+func LoadCubemapTexture(filePath string) (CubemapTexture, error) {
+	id, err := genCubemapTexture(filePath)
+	if err != nil {
+		return CubemapTexture{}, err
+	}
+
+	return CubemapTexture{id}, nil
 }
 
 func genCubemapTexture(filePath string) (uint32, error) {
-	filePath = "../res/textures/" + filePath
+	filePath = path.Join("textures", filePath)
 
 	var cubemapTexture uint32
 	gl.GenTextures(1, &cubemapTexture)
 	gl.BindTexture(gl.TEXTURE_CUBE_MAP, cubemapTexture)
 
-	imageNames := []string{"/right.png", "/left.png", "/top.png", "/bottom.png", "/front.png", "/back.png"}
+	imageNames := []string{"right.png", "left.png", "top.png", "bottom.png", "front.png", "back.png"}
 
 	for i, imagePath := range imageNames {
-		imgFile, err := os.Open(filePath + imagePath)
-		if err != nil {
-			return 0, fmt.Errorf("texture %q not found on disk: %v", filePath, err)
-		}
-		img, _, err := image.Decode(imgFile)
+		rgba, err := loadCubemapFace(path.Join(filePath, imagePath))
 		if err != nil {
+			gl.DeleteTextures(1, &cubemapTexture)
 			return 0, err
 		}
 
-		rgba := image.NewRGBA(img.Bounds())
-		if rgba.Stride != rgba.Rect.Size().X*4 {
-			return 0, fmt.Errorf("unsupported stride")
-		}
-		draw.Draw(rgba, rgba.Bounds(), img, image.Point{0, 0}, draw.Src)
-
 		gl.TexImage2D(
 			gl.TEXTURE_CUBE_MAP_POSITIVE_X+uint32(i),
 			0,
@@ -70,12 +72,34 @@ func genCubemapTexture(filePath string) (uint32, error) {
 	return cubemapTexture, nil
 }
 
-func (t *CubemapTexture) bind(slot uint32) {
+// Reads one face of a cubemap
+func loadCubemapFace(filePath string) (*image.RGBA, error) {
+	imgFile, err := Assets.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("texture %q not found on disk: %v", filePath, err)
+	}
+	defer imgFile.Close()
+
+	img, _, err := image.Decode(imgFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode texture %q: %v", filePath, err)
+	}
+
+	rgba := image.NewRGBA(img.Bounds())
+	if rgba.Stride != rgba.Rect.Size().X*4 {
+		return nil, fmt.Errorf("texture %q has an unsupported stride", filePath)
+	}
+	draw.Draw(rgba, rgba.Bounds(), img, image.Point{0, 0}, draw.Src)
+
+	return rgba, nil
+}
+
+func (t *CubemapTexture) Bind(slot uint32) {
 	gl.ActiveTexture(gl.TEXTURE0 + slot)
 	gl.BindTexture(gl.TEXTURE_CUBE_MAP, t.id)
 }
 
-func (t *CubemapTexture) unbind(slot uint32) {
+func (t *CubemapTexture) Unbind(slot uint32) {
 	gl.ActiveTexture(gl.TEXTURE0 + slot)
 	gl.BindTexture(gl.TEXTURE_CUBE_MAP, 0)
 }
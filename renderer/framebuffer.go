@@ -1,4 +1,4 @@
-package main
+package renderer
 
 import (
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -45,7 +45,7 @@ Parameters:
 - colorAttachment: what color attachment the texture will use
 - pixelSize: the size of the internal format
 */
-func (fb *FrameBuffer) addColorTexture(slot uint32, texWidth uint32, texHeight uint32, colorAttachment uint32, pixelSize int32) {
+func (fb *FrameBuffer) AddColorTexture(slot uint32, texWidth uint32, texHeight uint32, colorAttachment uint32, pixelSize int32) {
 	// Create new texture
 	var tex uint32
 	gl.GenTextures(1, &tex)
@@ -62,12 +62,12 @@ func (fb *FrameBuffer) addColorTexture(slot uint32, texWidth uint32, texHeight u
 
 	gl.TexImage2D(gl.TEXTURE_2D, 0, pixelSize, int32(texWidth), int32(texHeight), 0, gl.RGBA, gl.UNSIGNED_BYTE, nil)
 
-	fb.bind()
+	fb.Bind()
 	gl.FramebufferTexture2D(gl.FRAMEBUFFER, colorAttachment, gl.TEXTURE_2D, tex, 0)
 
 	fb.m_DrawBuffers = append(fb.m_DrawBuffers, colorAttachment)
 	gl.DrawBuffers(int32(len(fb.m_DrawBuffers)), &fb.m_DrawBuffers[0])
-	fb.unbind()
+	fb.Unbind()
 }
 
 /*
@@ -78,7 +78,7 @@ Parameters:
 - texWidth: the width of the texture
 - texHeight: the height of the texture
 */
-func (fb *FrameBuffer) addDepthTexture(slot uint32, texWidth uint32, texHeight uint32) {
+func (fb *FrameBuffer) AddDepthTexture(slot uint32, texWidth uint32, texHeight uint32) {
 	// Create new texture
 	var tex uint32
 	gl.GenTextures(1, &tex)
@@ -95,25 +95,25 @@ func (fb *FrameBuffer) addDepthTexture(slot uint32, texWidth uint32, texHeight u
 
 	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.DEPTH_COMPONENT32, int32(texWidth), int32(texHeight), 0, gl.DEPTH_COMPONENT, gl.FLOAT, nil)
 
-	fb.bind()
+	fb.Bind()
 	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.DEPTH_ATTACHMENT, gl.TEXTURE_2D, tex, 0)
-	fb.unbind()
+	fb.Unbind()
 }
 
-func (fb *FrameBuffer) addRenderBuffer(rb uint32) {
+func (fb *FrameBuffer) AddRenderBuffer(rb uint32) {
 	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.DEPTH_STENCIL_ATTACHMENT, gl.RENDERBUFFER, rb)
 }
 
-func (fb *FrameBuffer) delete() {
+func (fb *FrameBuffer) Delete() {
 	gl.DeleteFramebuffers(1, &fb.id)
 }
 
-func (fb *FrameBuffer) bind() {
+func (fb *FrameBuffer) Bind() {
 	gl.Viewport(0, 0, int32(fb.width), int32(fb.height))
 
 	gl.BindFramebuffer(gl.FRAMEBUFFER, fb.id)
 }
 
-func (fb *FrameBuffer) unbind() {
+func (fb *FrameBuffer) Unbind() {
 	gl.BindFramebuffer(gl.FRAMEBUFFER, 0)
 }
@@ -0,0 +1,298 @@
+package renderer
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/glfw/v3.3/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"stensvad-ossianst-melvinbe-project/scene"
+)
+
+// The golden images are rendered offscreen in a hidden window. On a machine without a display, run the tests
+// in a virtual one with software rendering, for example with Mesa llvmpipe:
+//
+//	LIBGL_ALWAYS_SOFTWARE=1 xvfb-run -a go test ./renderer
+//
+// After an intended change to the shaders or the pipeline, rewrite the golden images with:
+//
+//	LIBGL_ALWAYS_SOFTWARE=1 xvfb-run -a go test ./renderer -run TestGolden -update
+var update = flag.Bool("update", false, "rewrite the golden images in testdata/golden instead of comparing against them")
+
+const (
+	goldenWidth  = 320
+	goldenHeight = 240
+
+	// How much a channel of a pixel may differ from the golden image, out of 255, to allow for driver differences
+	goldenChannelTolerance = 8
+	// How many pixels may differ by more than the channel tolerance
+	goldenPixelTolerance = 0.005
+)
+
+// Every GL call must be made on the thread that owns the context, which is the main thread
+var mainThread = make(chan func())
+
+// Why the tests that need OpenGL are skipped, empty if there is a context
+var noContext string
+
+func init() {
+	runtime.LockOSThread()
+}
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	window, err := createHiddenWindow()
+	if err != nil {
+		noContext = err.Error()
+	}
+
+	// Run the tests on another goroutine and serve their GL calls here
+	exit := make(chan int)
+	go func() {
+		exit <- m.Run()
+	}()
+
+	for {
+		select {
+		case f := <-mainThread:
+			f()
+		case code := <-exit:
+			if window != nil {
+				window.Destroy()
+				glfw.Terminate()
+			}
+			os.Exit(code)
+		}
+	}
+}
+
+// Creates a hidden window with the same OpenGL context as the viewer
+func createHiddenWindow() (*glfw.Window, error) {
+	if err := glfw.Init(); err != nil {
+		return nil, fmt.Errorf("failed to initialize glfw: %v", err)
+	}
+
+	glfw.WindowHint(glfw.Visible, glfw.False)
+	glfw.WindowHint(glfw.Resizable, glfw.False)
+	glfw.WindowHint(glfw.ContextVersionMajor, 4)
+	glfw.WindowHint(glfw.ContextVersionMinor, 1)
+	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
+	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
+	window, err := glfw.CreateWindow(goldenWidth, goldenHeight, "golden", nil, nil)
+	if err != nil {
+		glfw.Terminate()
+		return nil, fmt.Errorf("failed to create a hidden window: %v", err)
+	}
+	window.MakeContextCurrent()
+
+	if err := gl.Init(); err != nil {
+		window.Destroy()
+		glfw.Terminate()
+		return nil, fmt.Errorf("failed to initialize OpenGL: %v", err)
+	}
+	ConfigureGL()
+
+	return window, nil
+}
+
+// Runs f on the main thread and waits for it to return
+func onMainThread(f func()) {
+	done := make(chan struct{})
+	mainThread <- func() {
+		defer close(done)
+		f()
+	}
+	<-done
+}
+
+func TestGolden(t *testing.T) {
+	if noContext != "" {
+		t.Skip("no OpenGL context:", noContext)
+	}
+
+	for _, name := range []string{"earth", "moon", "sun"} {
+		t.Run(name, func(t *testing.T) {
+			s, err := scene.Load(filepath.Join("testdata", "scenes", name+".yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var img *image.NRGBA
+			onMainThread(func() {
+				img, err = renderScene(&s)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			compareGolden(t, filepath.Join("testdata", "golden", name+".png"), img)
+		})
+	}
+}
+
+// Planets generated in the background look the same as planets generated right away, once they are uploaded
+func TestGoldenInBackground(t *testing.T) {
+	if noContext != "" {
+		t.Skip("no OpenGL context:", noContext)
+	}
+
+	s, err := scene.Load(filepath.Join("testdata", "scenes", "earth.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cam := NewCamera(goldenWidth, goldenHeight, mgl32.Vec3(s.Camera.Position))
+
+	var system *SolarSystem
+	onMainThread(func() {
+		system, err = LoadSolarSystemInBackground(&s, &cam, goldenWidth, goldenHeight, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer system.Close()
+
+	for job := range system.pending {
+		<-job.Done()
+	}
+
+	var img *image.NRGBA
+	onMainThread(func() {
+		if err = system.Upload(&cam); err == nil {
+			img = drawFrame(system, &cam)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if system.Loading() {
+		t.Fatal("planets are still loading after every job is done")
+	}
+
+	if *update {
+		// The golden image belongs to TestGolden
+		return
+	}
+	compareGolden(t, filepath.Join("testdata", "golden", "earth.png"), img)
+}
+
+// Draws one frame of a scene with a camera that does not move and time that does not pass
+func renderScene(s *scene.Scene) (*image.NRGBA, error) {
+	cam := NewCamera(goldenWidth, goldenHeight, mgl32.Vec3(s.Camera.Position))
+
+	system, err := LoadSolarSystem(s, &cam, goldenWidth, goldenHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	return drawFrame(system, &cam), nil
+}
+
+// Draws one frame of a solar system and reads it back
+func drawFrame(system *SolarSystem, cam *Camera) *image.NRGBA {
+	gl.Viewport(0, 0, goldenWidth, goldenHeight)
+	system.Draw(cam)
+	gl.Finish()
+
+	return ReadPixels(goldenWidth, goldenHeight)
+}
+
+// Compares an image against a golden image, or rewrites the golden image with -update
+func compareGolden(t *testing.T, path string, img *image.NRGBA) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := writePNG(path, img); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("wrote %s", path)
+		return
+	}
+
+	golden, err := readPNG(path)
+	if os.IsNotExist(err) {
+		t.Skipf("%s does not exist, create it with -update", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if golden.Bounds() != img.Bounds() {
+		t.Fatalf("rendered %v, but %s is %v", img.Bounds().Size(), path, golden.Bounds().Size())
+	}
+
+	// Count the pixels that differ by more than the tolerance and mark them red in a diff image
+	diff := image.NewNRGBA(img.Bounds())
+	different := 0
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			a := img.NRGBAAt(x, y)
+			b := color.NRGBAModel.Convert(golden.At(x, y)).(color.NRGBA)
+
+			if channelDiff(a.R, b.R) > goldenChannelTolerance || channelDiff(a.G, b.G) > goldenChannelTolerance || channelDiff(a.B, b.B) > goldenChannelTolerance {
+				different++
+				diff.SetNRGBA(x, y, color.NRGBA{255, 0, 0, 255})
+			} else {
+				diff.SetNRGBA(x, y, color.NRGBA{a.R / 4, a.G / 4, a.B / 4, 255})
+			}
+		}
+	}
+
+	total := img.Bounds().Dx() * img.Bounds().Dy()
+	if float64(different) > goldenPixelTolerance*float64(total) {
+		// Keep the images so that the difference can be inspected
+		dir := filepath.Join(os.TempDir(), "planets-golden")
+		name := filepath.Base(path[:len(path)-len(filepath.Ext(path))])
+		actualPath := filepath.Join(dir, name+"_actual.png")
+		diffPath := filepath.Join(dir, name+"_diff.png")
+		if err := os.MkdirAll(dir, 0o755); err == nil {
+			writePNG(actualPath, img)
+			writePNG(diffPath, diff)
+		}
+
+		t.Errorf("%d of %d pixels differ from %s, see %s and %s", different, total, path, actualPath, diffPath)
+	}
+}
+
+func channelDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
+
+func readPNG(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return png.Decode(file)
+}
+
+func writePNG(path string, img image.Image) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
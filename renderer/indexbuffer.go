@@ -1,4 +1,4 @@
-package main
+package renderer
 
 import (
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -13,21 +13,21 @@ func NewIndexBuffer(indices []uint32) IndexBuffer {
 	var id uint32
 	gl.GenBuffers(1, &id)
 	ib := IndexBuffer{id, int32(len(indices))}
-	ib.bind()
+	ib.Bind()
 	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
-	ib.unbind()
+	ib.Unbind()
 
 	return ib
 }
 
-func (ib *IndexBuffer) delete() {
+func (ib *IndexBuffer) Delete() {
 	gl.DeleteBuffers(1, &ib.id)
 }
 
-func (ib *IndexBuffer) bind() {
+func (ib *IndexBuffer) Bind() {
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ib.id)
 }
 
-func (ib *IndexBuffer) unbind() {
+func (ib *IndexBuffer) Unbind() {
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
 }
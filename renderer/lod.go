@@ -0,0 +1,79 @@
+package renderer
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+)
+
+// The buffers of a terrain chunk
+type chunkMesh struct {
+	vb VertexBuffer
+	va VertexArray
+}
+
+// The surface of a planet drawn as chunks that get finer close to the camera
+type lodTerrain struct {
+	terrain *generation.LODTerrain
+	chunks  map[generation.ChunkKey]*chunkMesh
+
+	// Every chunk has the same indices, for every set of stitched edges
+	indices [16]IndexBuffer
+}
+
+// Generates the chunks that cover a whole planet, which are split as the camera comes closer
+func newLODTerrain(shape generation.PlanetShape) (*lodTerrain, error) {
+	terrain, err := generation.NewLODTerrain(shape)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &lodTerrain{terrain, map[generation.ChunkKey]*chunkMesh{}, [16]IndexBuffer{}}
+	for stitch := range t.indices {
+		t.indices[stitch] = NewIndexBuffer(generation.ChunkIndices(uint8(stitch)))
+	}
+
+	return t, nil
+}
+
+// Updates the chunks for the position of the camera and draws them with the material of a sprite
+func (t *lodTerrain) draw(s *Sprite, cam *Camera, position, rotation mgl32.Vec3, scale float32) {
+	model := modelMatrix(position, rotation, scale)
+
+	// Chunks are split by the distance to the camera relative to the planet
+	local := model.Inv().Mul4x1(cam.GetPosition().Vec4(1)).Vec3()
+	chunks := t.terrain.Update(local)
+
+	// Upload the new chunks, and delete the chunks that have been split or merged
+	drawn := map[generation.ChunkKey]bool{}
+	for _, chunk := range chunks {
+		drawn[chunk.Key] = true
+		if t.chunks[chunk.Key] == nil {
+			vb := NewVertexBuffer(chunk.Vertices)
+			vb.Bind()
+			t.chunks[chunk.Key] = &chunkMesh{vb, newSpriteVertexArray()}
+			vb.Unbind()
+		}
+	}
+	for key, mesh := range t.chunks {
+		if !drawn[key] {
+			mesh.va.Delete()
+			mesh.vb.Delete()
+			delete(t.chunks, key)
+		}
+	}
+
+	s.bind(cam, model)
+	for _, chunk := range chunks {
+		mesh := t.chunks[chunk.Key]
+		ib := &t.indices[chunk.Stitch]
+
+		mesh.va.Bind()
+		ib.Bind()
+		gl.DrawElements(gl.TRIANGLES, ib.count, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	}
+	s.va.Unbind()
+	t.indices[0].Unbind()
+	s.shader.Unbind()
+}
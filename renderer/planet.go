@@ -0,0 +1,271 @@
+package renderer
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+)
+
+type Planet struct {
+	sprite Sprite
+	water  *Sprite     // the lakes and rivers, nil if the planet has none
+	lod    *lodTerrain // the surface drawn in chunks in place of the sprite mesh, nil if the planet has no levels of detail
+
+	position mgl32.Vec3
+	rotation mgl32.Vec3
+	scale    float32
+
+	axisAroundParent mgl32.Vec3
+	orbital          []*Planet
+	orbitTime        float64
+
+	hasAtmosphere bool
+}
+
+/*
+NewPlanet generates a new planet struct and returns it
+
+Parameters:
+- settings: the planet settings struct containing a recipe for what the planet will be
+- cam: the camera the planet is seen through
+
+Returns:
+- p: the new planet object
+
+Example usage:
+
+	earthSettings := generation.DefaultEarth()
+	planet := NewPlanet(earthSettings, cam)
+*/
+func NewPlanet(settings generation.PlanetSettings, cam *Camera) *Planet {
+	p, err := LoadPlanet(settings, cam)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// LoadPlanet is like NewPlanet but returns an error if the terrain graph is invalid or the textures or the shader of the planet could not be loaded
+func LoadPlanet(settings generation.PlanetSettings, cam *Camera) (*Planet, error) {
+	if settings.Shape.LODLevels > 0 {
+		return loadLODPlanet(settings, cam)
+	}
+
+	// Generate the planet sprite model
+	planetVertices, planetIndices, water, err := generation.GenPlanetWithWater(settings.Shape)
+	if err != nil {
+		return nil, err
+	}
+
+	sprite, err := LoadSprite(
+		planetVertices,
+		planetIndices,
+		settings.TexturePath,
+		settings.NormalMapPath,
+		settings.ShaderPath,
+		settings.TextureScale,
+		settings.NormalMapScale,
+		cam,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Planet{
+		sprite,
+		nil,
+		nil,
+
+		mgl32.Vec3{0.0, 0.0, 0.0},
+		mgl32.Vec3{0.0, 0.0, 0.0},
+		settings.Shape.Radius,
+
+		mgl32.Vec3{},
+		nil,
+		0,
+		settings.HasAtmosphere,
+	}
+
+	if err := p.loadWater(water, settings, cam); err != nil {
+		return nil, err
+	}
+	p.SetColors(settings.Colors)
+
+	return p, nil
+}
+
+// How many times the sides of the placeholder sphere are divided
+const placeholderResolution = 16
+
+// Loads a planet that is drawn as a smooth sphere until its mesh has been generated and is uploaded
+func loadPlaceholderPlanet(settings generation.PlanetSettings, cam *Camera) (*Planet, error) {
+	vertices, indices := generation.GenPlaceholder(placeholderResolution)
+
+	sprite, err := LoadSprite(
+		vertices,
+		indices,
+		settings.TexturePath,
+		settings.NormalMapPath,
+		settings.ShaderPath,
+		settings.TextureScale,
+		settings.NormalMapScale,
+		cam,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Planet{
+		sprite,
+		nil,
+		nil,
+
+		mgl32.Vec3{0.0, 0.0, 0.0},
+		mgl32.Vec3{0.0, 0.0, 0.0},
+		settings.Shape.Radius,
+
+		mgl32.Vec3{},
+		nil,
+		0,
+		settings.HasAtmosphere,
+	}
+
+	p.SetColors(settings.Colors)
+
+	return p, nil
+}
+
+// Replaces the mesh of the planet with a generated one, along with its lakes and rivers
+func (p *Planet) upload(mesh generation.PlanetMesh, settings generation.PlanetSettings, cam *Camera) error {
+	p.sprite.setMesh(mesh.Vertices, mesh.Indices)
+
+	if err := p.loadWater(mesh.Water, settings, cam); err != nil {
+		return err
+	}
+	p.SetColors(settings.Colors)
+
+	return nil
+}
+
+// Loads the sprite of the lakes and rivers of the planet, if it has any
+func (p *Planet) loadWater(water generation.Hydrology, settings generation.PlanetSettings, cam *Camera) error {
+	if len(water.WaterIndices) == 0 {
+		return nil
+	}
+
+	s, err := LoadSprite(
+		water.WaterVertices,
+		water.WaterIndices,
+		settings.TexturePath,
+		settings.NormalMapPath,
+		"water.shader",
+		settings.TextureScale,
+		settings.NormalMapScale,
+		cam,
+	)
+	if err != nil {
+		return err
+	}
+	p.water = &s
+
+	return nil
+}
+
+// Loads a planet whose surface is drawn in chunks that get finer close to the camera.
+// The chunks are sampled straight from the terrain, so the planet has no lakes or rivers to draw.
+func loadLODPlanet(settings generation.PlanetSettings, cam *Camera) (*Planet, error) {
+	lod, err := newLODTerrain(settings.Shape)
+	if err != nil {
+		return nil, err
+	}
+
+	sprite, err := loadSpriteMaterial(
+		settings.TexturePath,
+		settings.NormalMapPath,
+		settings.ShaderPath,
+		settings.TextureScale,
+		settings.NormalMapScale,
+		cam,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Planet{
+		sprite,
+		nil,
+		lod,
+
+		mgl32.Vec3{0.0, 0.0, 0.0},
+		mgl32.Vec3{0.0, 0.0, 0.0},
+		settings.Shape.Radius,
+
+		mgl32.Vec3{},
+		nil,
+		0,
+		settings.HasAtmosphere,
+	}
+
+	p.SetColors(settings.Colors)
+
+	return p, nil
+}
+
+// Binds the color uniforms of the "planet.shader" shader to the planets colors
+func (p *Planet) SetColors(c generation.PlanetColors) {
+	p.sprite.shader.Bind()
+
+	p.sprite.shader.SetUniform3f("shoreColLow", c.ShoreColLow.X(), c.ShoreColLow.Y(), c.ShoreColLow.Z())
+	p.sprite.shader.SetUniform3f("shoreColHigh", c.ShoreColHigh.X(), c.ShoreColHigh.Y(), c.ShoreColHigh.Z())
+	p.sprite.shader.SetUniform3f("flatColLow", c.FlatColLow.X(), c.FlatColLow.Y(), c.FlatColLow.Z())
+	p.sprite.shader.SetUniform3f("flatColHigh", c.FlatColHigh.X(), c.FlatColHigh.Y(), c.FlatColHigh.Z())
+	p.sprite.shader.SetUniform3f("steepColLow", c.SteepColLow.X(), c.SteepColLow.Y(), c.SteepColLow.Z())
+	p.sprite.shader.SetUniform3f("steepColHigh", c.SteepColHigh.X(), c.SteepColHigh.Y(), c.SteepColHigh.Z())
+	p.sprite.shader.SetUniform3f("waterCol", c.WaterCol.X(), c.WaterCol.Y(), c.WaterCol.Z())
+	p.sprite.shader.SetUniform3f("iceCol", c.IceCol.X(), c.IceCol.Y(), c.IceCol.Z())
+
+	for _, biome := range generation.Biomes {
+		col := biome.Color()
+		p.sprite.shader.SetUniform3f(fmt.Sprintf("biomeCols[%d]", biome), col.X(), col.Y(), col.Z())
+	}
+
+	if p.water != nil {
+		p.water.shader.Bind()
+		p.water.shader.SetUniform3f("waterCol", c.WaterCol.X(), c.WaterCol.Y(), c.WaterCol.Z())
+	}
+}
+
+// Add an orbital to this planet
+func (p *Planet) AddOrbital(planet *Planet, distance float32, axis mgl32.Vec3, timeToOrbit float64) {
+	planet.axisAroundParent = axis.Normalize()
+	planet.orbitTime = timeToOrbit
+	planet.position = planet.axisAroundParent.Cross(mgl32.Vec3{1, 1, 1}).Normalize().Mul(distance)
+	p.orbital = append(p.orbital, planet)
+}
+
+// Draws planet and its orbitals
+func (p *Planet) Draw(cam *Camera) {
+	if p.lod != nil {
+		p.lod.draw(&p.sprite, cam, p.position, p.rotation, p.scale)
+	} else {
+		p.sprite.Draw(cam, p.position, p.rotation, p.scale)
+	}
+	if p.water != nil {
+		p.water.Draw(cam, p.position, p.rotation, p.scale)
+	}
+
+	p.rotation = mgl32.Vec3{0, float32(cam.TimeTot), 0}
+
+	// Draw and rotate all orbitals around this planet
+	for i := range p.orbital {
+		rotM := mgl32.HomogRotate3D(float32(cam.TimeDiff*p.orbital[i].orbitTime), p.orbital[i].axisAroundParent)
+		p.orbital[i].position = rotM.Mul4x1(p.orbital[i].position.Vec4(0)).Vec3().Add(p.position)
+
+		p.orbital[i].Draw(cam)
+
+		p.orbital[i].position = p.orbital[i].position.Sub(p.position)
+	}
+}
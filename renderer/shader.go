@@ -0,0 +1,266 @@
+package renderer
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type Shader struct {
+	id uint32
+}
+
+// ShaderError describes a shader file that could not be read, compiled or linked
+type ShaderError struct {
+	File  string // the .shader file
+	Stage string // "vertex", "fragment" or "link"
+	Log   string // the info log of the driver, with line numbers pointing into the .shader file
+}
+
+func (e *ShaderError) Error() string {
+	if e.Stage == "link" {
+		return fmt.Sprintf("failed to link shader program %s:\n%s", e.File, e.Log)
+	}
+	return fmt.Sprintf("failed to compile %s shader of %s:\n%s", e.Stage, e.File, e.Log)
+}
+
+// The source of one stage in a .shader file
+type shaderStage struct {
+	name      string
+	source    string
+	firstLine int // the line in the .shader file that the source starts at
+}
+
+// Creates a new shader program and returns it. Panics if something goes wrong while loading the shader.
+func NewShader(filePath string) Shader {
+	shader, err := LoadShader(filePath)
+	if err != nil {
+		panic(err)
+	}
+
+	return shader
+}
+
+/*
+LoadShader reads, compiles and links the vertex and fragment shaders of a .shader file
+
+Parameters:
+- filePath: path to the shader file from the "shaders" folder of the assets
+
+Returns:
+- shader: the new shader program
+- err: a *ShaderError if a stage could not be compiled or linked, or the error from reading the file
+
+Example usage:
+
+	shader, err := LoadShader("planet.shader")
+*/
+func LoadShader(filePath string) (Shader, error) {
+	filePath = path.Join("shaders", filePath)
+	vertexStage, fragmentStage, err := parseShader(filePath)
+	if err != nil {
+		return Shader{}, err
+	}
+
+	id, err := newProgram(filePath, vertexStage, fragmentStage)
+	if err != nil {
+		return Shader{}, err
+	}
+
+	return Shader{id}, nil
+}
+
+func parseShader(filePath string) (vertexShader, fragmentShader shaderStage, err error) {
+	// Read content of file
+	content, err := fs.ReadFile(Assets, filePath)
+	if err != nil {
+		return shaderStage{}, shaderStage{}, fmt.Errorf("failed to read shader: %v", err)
+	}
+
+	// Two builders for storing vertex and fragment shaders
+	var sb [2]strings.Builder
+	var currentShader *strings.Builder
+	stages := [2]shaderStage{{name: "vertex"}, {name: "fragment"}}
+
+	// Iterate over each line in content
+	for i, line := range strings.Split(string(content), "\n") {
+		// Check if line is first line of a shader
+		if strings.HasPrefix(line, "#shader") {
+			// Determine shader type based on line content, the shader source starts on the next line
+			if strings.Contains(line, "vertex") {
+				currentShader = &sb[0] // Set current shader builder to vertex
+				stages[0].firstLine = i + 2
+			} else if strings.Contains(line, "fragment") {
+				currentShader = &sb[1] // Set current shader builder to fragment
+				stages[1].firstLine = i + 2
+			}
+		} else if currentShader != nil {
+			// If we are inside a shader block, append line to current shader builder
+			currentShader.WriteString(line + "\n")
+		}
+	}
+
+	for i := range stages {
+		if stages[i].firstLine == 0 {
+			return shaderStage{}, shaderStage{}, &ShaderError{filePath, stages[i].name, "missing \"#shader " + stages[i].name + "\" section"}
+		}
+		// Null terminate the sources
+		stages[i].source = sb[i].String() + "\x00"
+	}
+
+	return stages[0], stages[1], nil
+}
+
+func newProgram(filePath string, vertexStage, fragmentStage shaderStage) (uint32, error) {
+	// Compile vertex shader
+	vertexShader, err := compileShader(filePath, vertexStage, gl.VERTEX_SHADER)
+	if err != nil {
+		return 0, err
+	}
+
+	// Compile fragment shader
+	fragmentShader, err := compileShader(filePath, fragmentStage, gl.FRAGMENT_SHADER)
+	if err != nil {
+		gl.DeleteShader(vertexShader)
+		return 0, err
+	}
+
+	program := gl.CreateProgram()
+
+	// Attach shaders to program
+	gl.AttachShader(program, vertexShader)
+	gl.AttachShader(program, fragmentShader)
+
+	gl.LinkProgram(program)
+
+	// Delete shaders as they are no longer needed
+	gl.DeleteShader(vertexShader)
+	gl.DeleteShader(fragmentShader)
+
+	var status int32
+	gl.GetProgramiv(program, gl.LINK_STATUS, &status)
+
+	// Check for errors
+	if status == gl.FALSE {
+		var logLength int32
+		gl.GetProgramiv(program, gl.INFO_LOG_LENGTH, &logLength)
+
+		log := strings.Repeat("\x00", int(logLength+1))
+		gl.GetProgramInfoLog(program, logLength, nil, gl.Str(log))
+		gl.DeleteProgram(program)
+
+		return 0, &ShaderError{filePath, "link", strings.TrimRight(log, "\x00\n")}
+	}
+
+	return program, nil
+}
+
+func compileShader(filePath string, stage shaderStage, shaderType uint32) (uint32, error) {
+	shader := gl.CreateShader(shaderType)
+
+	// Convert source string to C-style string
+	csources, free := gl.Strs(stage.source)
+
+	// Set shader source code
+	gl.ShaderSource(shader, 1, csources, nil)
+
+	// Free C-style string resources
+	free()
+
+	gl.CompileShader(shader)
+
+	var status int32
+	gl.GetShaderiv(shader, gl.COMPILE_STATUS, &status)
+
+	// Check for errors
+	if status == gl.FALSE {
+		var logLength int32
+		gl.GetShaderiv(shader, gl.INFO_LOG_LENGTH, &logLength)
+
+		log := strings.Repeat("\x00", int(logLength+1))
+		gl.GetShaderInfoLog(shader, logLength, nil, gl.Str(log))
+		gl.DeleteShader(shader)
+
+		log = mapShaderLog(strings.TrimRight(log, "\x00\n"), path.Base(filePath), stage.firstLine)
+		return 0, &ShaderError{filePath, stage.name, log}
+	}
+
+	return shader, nil
+}
+
+// Line numbers in info logs look like "0:12(5):" (Mesa), "ERROR: 0:12:" (AMD, Intel and Apple) or "0(12) :" (Nvidia)
+var shaderLogLine = regexp.MustCompile(`^(ERROR: |WARNING: )?\d+(:(\d+)|\((\d+)\))`)
+
+// Replaces the line numbers of an info log with the file name and line number in the .shader file
+func mapShaderLog(log, fileName string, firstLine int) string {
+	lines := strings.Split(log, "\n")
+
+	for i, line := range lines {
+		match := shaderLogLine.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+
+		// The line number is either the third or the fourth group
+		start, end := match[6], match[7]
+		if start < 0 {
+			start, end = match[8], match[9]
+		}
+		lineNumber, err := strconv.Atoi(line[start:end])
+		if err != nil {
+			continue
+		}
+
+		prefix := ""
+		if match[2] >= 0 {
+			prefix = line[match[2]:match[3]]
+		}
+		lines[i] = fmt.Sprintf("%s%s:%d%s", prefix, fileName, lineNumber+firstLine-1, line[match[1]:])
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (s *Shader) SetUniform1i(name string, value int32) {
+	location := gl.GetUniformLocation(s.id, gl.Str(name+"\x00"))
+	gl.Uniform1i(location, value)
+}
+
+func (s *Shader) SetUniform1f(name string, value float32) {
+	location := gl.GetUniformLocation(s.id, gl.Str(name+"\x00"))
+	gl.Uniform1f(location, value)
+}
+
+func (s *Shader) SetUniform2f(name string, v0, v1 float32) {
+	location := gl.GetUniformLocation(s.id, gl.Str(name+"\x00"))
+	gl.Uniform2f(location, v0, v1)
+}
+
+func (s *Shader) SetUniform3f(name string, v0, v1, v2 float32) {
+	location := gl.GetUniformLocation(s.id, gl.Str(name+"\x00"))
+	gl.Uniform3f(location, v0, v1, v2)
+}
+
+func (s *Shader) SetUniform4f(name string, v0, v1, v2, v3 float32) {
+	location := gl.GetUniformLocation(s.id, gl.Str(name+"\x00"))
+	gl.Uniform4f(location, v0, v1, v2, v3)
+}
+
+func (s *Shader) SetUniformMat4fv(name string, matrix mgl32.Mat4) {
+	location := gl.GetUniformLocation(s.id, gl.Str(name+"\x00"))
+	gl.UniformMatrix4fv(location, 1, false, &matrix[0])
+}
+
+func (s *Shader) Bind() {
+	gl.UseProgram(s.id)
+}
+
+func (s *Shader) Unbind() {
+	gl.UseProgram(0)
+}
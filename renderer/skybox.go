@@ -0,0 +1,114 @@
+package renderer
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+type Skybox struct {
+	texture CubemapTexture
+	shader  Shader
+
+	vb VertexBuffer
+	ib IndexBuffer
+	va VertexArray
+}
+
+// Creates a new skybox and returns it. Panics if something goes wrong while loading the cubemap or the shader.
+func NewSkybox(texturePath string, shaderPath string, cam *Camera) Skybox {
+	s, err := LoadSkybox(texturePath, shaderPath, cam)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// LoadSkybox creates a new skybox from a cubemap folder in the "textures" folder, or returns an error if
+// the cubemap or the shader could not be loaded
+func LoadSkybox(texturePath string, shaderPath string, cam *Camera) (Skybox, error) {
+	texture, err := LoadCubemapTexture(texturePath)
+	if err != nil {
+		return Skybox{}, err
+	}
+	shader, err := LoadShader(shaderPath)
+	if err != nil {
+		return Skybox{}, err
+	}
+
+	var vertices = []float32{
+		// Positions
+		-1, -1, 1,
+		1, -1, 1,
+		1, -1, -1,
+		-1, -1, -1,
+		-1, 1, 1,
+		1, 1, 1,
+		1, 1, -1,
+		-1, 1, -1,
+	}
+
+	var indices = []uint32{
+		2, 1, 6,
+		5, 6, 1,
+		4, 0, 7,
+		3, 7, 0,
+		5, 4, 6,
+		7, 6, 4,
+		3, 0, 2,
+		1, 2, 0,
+		1, 0, 5,
+		4, 5, 0,
+		7, 3, 6,
+		2, 6, 3,
+	}
+
+	s := Skybox{
+		texture,
+		shader,
+		VertexBuffer{0},
+		IndexBuffer{0, 0},
+		VertexArray{0},
+	}
+	s.shader.Bind()
+	projection := cam.ProjMatrix()
+	view := cam.ViewMatrix().Mat3().Mat4()
+
+	s.shader.SetUniformMat4fv("view", view)
+	s.shader.SetUniformMat4fv("projection", projection)
+
+	s.shader.Unbind()
+
+	s.vb = NewVertexBuffer(vertices)
+	s.ib = NewIndexBuffer(indices)
+
+	s.vb.Bind()
+	s.va = NewVertexArray([]int{3})
+
+	return s, nil
+}
+
+func (s *Skybox) Draw(cam *Camera) {
+	gl.DepthFunc(gl.LEQUAL)
+
+	s.texture.Bind(0)
+
+	view := cam.ViewMatrix().Mat3().Mat4()
+
+	projection := cam.ProjMatrix()
+
+	s.shader.Bind()
+
+	s.shader.SetUniformMat4fv("view", view)
+	s.shader.SetUniformMat4fv("projection", projection)
+
+	s.va.Bind()
+	s.ib.Bind()
+
+	gl.DrawElements(gl.TRIANGLES, s.ib.count, gl.UNSIGNED_INT, gl.PtrOffset(0))
+
+	s.va.Unbind()
+	s.ib.Unbind()
+	s.shader.Unbind()
+
+	gl.DepthFunc(gl.LESS)
+}
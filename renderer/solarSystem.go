@@ -0,0 +1,277 @@
+package renderer
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"unsafe"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+	"stensvad-ossianst-melvinbe-project/scene"
+)
+
+// SolarSystem is a scene that has been built and is ready to be drawn
+type SolarSystem struct {
+	sun        *Planet
+	skybox     Skybox
+	atmosphere PostProcessingFrame
+
+	planetsWithAtmosphere []*Planet
+	planetPositions       []mgl32.Vec4
+
+	// The planets that are generated in the background, nil if the system was loaded right away
+	jobs    *generation.JobQueue
+	pending map[*generation.PlanetJob]pendingBody
+}
+
+// A body whose planet is drawn as a placeholder until its mesh has been generated
+type pendingBody struct {
+	name     string
+	planet   *Planet
+	settings generation.PlanetSettings
+}
+
+// LoadProgress is told how much of the planet of a body has been generated, from 0 to 1
+type LoadProgress func(body string, fraction float32)
+
+/*
+NewSolarSystem generates every body of a scene, sets up their orbits and creates the skybox and
+the atmosphere post processing frame. Needs an OpenGL context.
+
+Parameters:
+- s: the scene to build
+- cam: the camera the solar system is seen through
+- fbWidth: the width of the frame buffer to draw to
+- fbHeight: the height of the frame buffer to draw to
+
+Returns:
+- system: the solar system, ready to be drawn
+
+Example usage:
+
+	s, err := scene.Load("res/scenes/solar.yaml")
+	system := NewSolarSystem(&s, cam, fbWidth, fbHeight)
+*/
+func NewSolarSystem(s *scene.Scene, cam *Camera, fbWidth, fbHeight int) *SolarSystem {
+	system, err := LoadSolarSystem(s, cam, fbWidth, fbHeight)
+	if err != nil {
+		panic(err)
+	}
+
+	return system
+}
+
+// LoadSolarSystem is like NewSolarSystem but returns an error naming the body whose assets could not be loaded
+func LoadSolarSystem(s *scene.Scene, cam *Camera, fbWidth, fbHeight int) (*SolarSystem, error) {
+	return loadSolarSystem(&SolarSystem{}, s, cam, fbWidth, fbHeight, func(body *scene.Body) (*Planet, error) {
+		return LoadPlanet(body.PlanetSettings(), cam)
+	})
+}
+
+/*
+LoadSolarSystemInBackground is like LoadSolarSystem but returns before the planets have been generated.
+The planets are generated on worker goroutines and drawn as smooth spheres until Upload is called after they are done.
+Planets with levels of detail are loaded right away, as their first chunks take no time to generate.
+
+Parameters:
+- s: the scene to build
+- cam: the camera the solar system is seen through
+- fbWidth: the width of the frame buffer to draw to
+- fbHeight: the height of the frame buffer to draw to
+- progress: called on the worker goroutines as the planets are generated, may be nil
+
+Returns:
+- system: the solar system, which has to be closed when it is no longer needed
+- err: an error naming the body whose assets could not be loaded
+
+Example usage:
+
+	system, err := LoadSolarSystemInBackground(&s, cam, fbWidth, fbHeight, nil)
+	defer system.Close()
+	for !window.ShouldClose() {
+		system.Upload(cam)
+		system.Draw(cam)
+	}
+*/
+func LoadSolarSystemInBackground(s *scene.Scene, cam *Camera, fbWidth, fbHeight int, progress LoadProgress) (*SolarSystem, error) {
+	system := &SolarSystem{
+		jobs:    generation.NewJobQueue(runtime.NumCPU()),
+		pending: map[*generation.PlanetJob]pendingBody{},
+	}
+
+	loaded, err := loadSolarSystem(system, s, cam, fbWidth, fbHeight, func(body *scene.Body) (*Planet, error) {
+		settings := body.PlanetSettings()
+		if settings.Shape.LODLevels > 0 {
+			return LoadPlanet(settings, cam)
+		}
+
+		planet, err := loadPlaceholderPlanet(settings, cam)
+		if err != nil {
+			return nil, err
+		}
+
+		name := body.Name
+		job := system.jobs.Submit(settings.Shape, func(fraction float32) {
+			if progress != nil {
+				progress(name, fraction)
+			}
+		})
+		system.pending[job] = pendingBody{name, planet, settings}
+
+		return planet, nil
+	})
+	if err != nil {
+		system.Close()
+		return nil, err
+	}
+
+	return loaded, nil
+}
+
+// Builds a solar system from the planets of the bodies of a scene, loaded by a function
+func loadSolarSystem(system *SolarSystem, s *scene.Scene, cam *Camera, fbWidth, fbHeight int, load func(body *scene.Body) (*Planet, error)) (*SolarSystem, error) {
+	planets := make([]*Planet, len(s.Bodies))
+	for i := range s.Bodies {
+		planet, err := load(&s.Bodies[i])
+		if err != nil {
+			return nil, fmt.Errorf("body %q: %v", s.Bodies[i].Name, err)
+		}
+		planets[i] = planet
+	}
+
+	root := s.Root()
+	system.sun = planets[root]
+
+	// Walk the tree from the sun outwards so that the sun is always first in the uniform buffer
+	queue := []int{root}
+	for len(queue) > 0 {
+		body := s.Bodies[queue[0]]
+		planet := planets[queue[0]]
+		queue = queue[1:]
+
+		for _, i := range s.Children(body.Name) {
+			orbit := s.Bodies[i].Orbit
+			speed := 2.0 * math.Pi / float64(orbit.Period)
+			planet.AddOrbital(planets[i], orbit.Distance, mgl32.Vec3(orbit.Axis), speed)
+			queue = append(queue, i)
+		}
+
+		if planet.hasAtmosphere {
+			system.planetsWithAtmosphere = append(system.planetsWithAtmosphere, planet)
+		}
+	}
+
+	// Send planet positions to uniform buffer
+	system.planetPositions = make([]mgl32.Vec4, len(system.planetsWithAtmosphere))
+	system.updatePlanetPositions()
+
+	atmosphere, err := LoadPostProcessingFrame(uint32(fbWidth), uint32(fbHeight), "atmosphere.shader", cam)
+	if err != nil {
+		return nil, fmt.Errorf("atmosphere: %v", err)
+	}
+	system.atmosphere = atmosphere
+	system.atmosphere.AddUniformBufferVec4("PlanetPositions", system.planetPositions, int(unsafe.Sizeof(mgl32.Vec4{}))*scene.MaxAtmospheres)
+
+	skybox, err := LoadSkybox(s.Skybox, "skybox.shader", cam)
+	if err != nil {
+		return nil, fmt.Errorf("skybox: %v", err)
+	}
+	system.skybox = skybox
+
+	return system, nil
+}
+
+/*
+Upload replaces the placeholders of the planets that have been generated since it was last called with their meshes.
+Needs the OpenGL context, so it is called from the render loop.
+
+Parameters:
+- cam: the camera the solar system is seen through
+
+Returns:
+- err: an error naming the body whose planet could not be generated or loaded, which is left as a placeholder
+*/
+func (s *SolarSystem) Upload(cam *Camera) error {
+	if s.jobs == nil {
+		return nil
+	}
+
+	var failed error
+	for _, job := range s.jobs.Finished() {
+		body := s.pending[job]
+		delete(s.pending, job)
+
+		err := job.Err
+		if err == nil {
+			err = body.planet.upload(job.Mesh, body.settings, cam)
+		}
+		if err != nil && failed == nil {
+			failed = fmt.Errorf("body %q: %v", body.name, err)
+		}
+	}
+
+	return failed
+}
+
+// Loading tells whether any planet is still being generated
+func (s *SolarSystem) Loading() bool {
+	return len(s.pending) > 0
+}
+
+// Close cancels the planets that are still being generated
+func (s *SolarSystem) Close() {
+	if s.jobs != nil {
+		s.jobs.Close()
+		s.pending = nil
+	}
+}
+
+// Draws every planet, the skybox and the atmospheres as seen through the camera
+func (s *SolarSystem) Draw(cam *Camera) {
+	camPos := cam.GetPosition()
+	sunPos := s.sun.position
+
+	// Send the world position, direction, projection matrix and view matrix of the camera
+	// as well as the position of the light to the atmosphere shader:
+	camDir := cam.GetOrientation()
+	s.atmosphere.shader.Bind()
+	s.atmosphere.shader.SetUniform3f("camDir", camDir.X(), camDir.Y(), camDir.Z())
+	s.atmosphere.shader.SetUniform3f("camPos", camPos.X(), camPos.Y(), camPos.Z())
+	s.atmosphere.shader.SetUniform3f("lightPos", sunPos.X(), sunPos.Y(), sunPos.Z())
+	s.atmosphere.shader.SetUniformMat4fv("viewMatrix", cam.ViewMatrix())
+	s.atmosphere.shader.SetUniformMat4fv("projMatrix", cam.ProjMatrix())
+
+	// Bind the framebuffer for postprocessing before drawing:
+	s.atmosphere.fb.Bind()
+
+	// Draw:
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	gl.Enable(gl.DEPTH_TEST)
+
+	s.sun.Draw(cam)
+
+	// Draw the skybox LAST
+	s.skybox.Draw(cam)
+
+	// Disable depth testing and apply post processing:
+	gl.Disable(gl.DEPTH_TEST)
+	s.atmosphere.fb.Unbind()
+
+	// Send planet properties to post processing shader:
+	s.updatePlanetPositions()
+	s.atmosphere.UpdateUniformBufferVec4(s.atmosphere.ub[0], s.planetPositions)
+
+	s.atmosphere.Draw()
+}
+
+// Copies the current planet positions to the vectors sent to the atmosphere shader
+func (s *SolarSystem) updatePlanetPositions() {
+	for i, planet := range s.planetsWithAtmosphere {
+		// First three are planet coordinates, fourth is planet scale
+		p := planet.position
+		s.planetPositions[i] = mgl32.Vec4{p.X(), p.Y(), p.Z(), planet.scale}
+	}
+}
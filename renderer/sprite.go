@@ -0,0 +1,170 @@
+package renderer
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type Sprite struct {
+	texture   Texture
+	normalMap Texture
+
+	shader Shader
+
+	vb VertexBuffer
+	ib IndexBuffer
+	va VertexArray
+}
+
+/*
+NewSprite generates a new sprite model object
+
+Parameters:
+- vertices: sprite vertices
+- indices: sprite indices
+- texturePath: path to texture file from "textures" folder
+- normalMapPath: path to normalmap file from "textures" folder
+- shaderPath: path to shader file map from "shaders" folder
+- textureScale: How often the texture wraps the model surface
+- normalMapScale: How often the normal map wraps the model surface
+- cam: the camera the sprite is seen through
+
+Returns:
+- s: the new sprite object
+
+Example usage:
+
+	vertices, indices, err := generation.GenPlanet(generation.DefaultEarth().Shape)
+	s := NewSprite(vertices, indices, "spots.png", "normalmap_rocky.png", "planet.shader", 1.0, 2.0, cam)
+*/
+func NewSprite(vertices []float32, indices []uint32, texturePath, normalMapPath, shaderPath string, textureScale, normalMapScale float32, cam *Camera) Sprite {
+	s, err := LoadSprite(vertices, indices, texturePath, normalMapPath, shaderPath, textureScale, normalMapScale, cam)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// LoadSprite is like NewSprite but returns an error if the textures or the shader could not be loaded
+func LoadSprite(vertices []float32, indices []uint32, texturePath, normalMapPath, shaderPath string, textureScale, normalMapScale float32, cam *Camera) (Sprite, error) {
+	s, err := loadSpriteMaterial(texturePath, normalMapPath, shaderPath, textureScale, normalMapScale, cam)
+	if err != nil {
+		return Sprite{}, err
+	}
+
+	s.setMesh(vertices, indices)
+
+	return s, nil
+}
+
+// Replaces the buffers of the sprite with buffers of new vertices and indices
+func (s *Sprite) setMesh(vertices []float32, indices []uint32) {
+	if s.va.id != 0 {
+		s.va.Delete()
+		s.vb.Delete()
+		s.ib.Delete()
+	}
+
+	// Generate index buffer and vertex array
+	s.vb = NewVertexBuffer(vertices)
+	s.ib = NewIndexBuffer(indices)
+
+	s.vb.Bind()
+	s.va = newSpriteVertexArray()
+}
+
+// Vertex arrays of sprites are laid out like the vertices from generation.GenPlanet
+func newSpriteVertexArray() VertexArray {
+	return NewVertexArray([]int{3, 3, 1, 1}) // A vertex contains a position(3 floats), a normal(3 floats), a biome(1 float) and ice(1 float)
+}
+
+// Loads the textures and the shader of a sprite without any buffers, for sprites that draw their own
+func loadSpriteMaterial(texturePath, normalMapPath, shaderPath string, textureScale, normalMapScale float32, cam *Camera) (Sprite, error) {
+	texture, err := LoadTexture(texturePath)
+	if err != nil {
+		return Sprite{}, err
+	}
+	normalMap, err := LoadTexture(normalMapPath)
+	if err != nil {
+		return Sprite{}, err
+	}
+	shader, err := LoadShader(shaderPath)
+	if err != nil {
+		return Sprite{}, err
+	}
+
+	s := Sprite{
+		texture,
+		normalMap,
+		shader,
+		VertexBuffer{0},
+		IndexBuffer{0, 0},
+		VertexArray{0},
+	}
+
+	// Set constant uniforms once
+	s.shader.Bind()
+
+	s.shader.SetUniform1i("mainTexture", 0)
+	s.shader.SetUniform1i("normalMap", 1)
+	s.shader.SetUniform1f("texScale", textureScale)
+	s.shader.SetUniform1f("nMapScale", normalMapScale)
+
+	s.shader.SetUniform3f("lightPos", 0.0, 0.0, 0.0)
+	s.shader.SetUniform3f("lightColor", 1.0, 1.0, 1.0)
+
+	s.shader.SetUniform1f("camFar", cam.farPlane)
+	s.shader.SetUniform1f("camNear", cam.nearPlane)
+
+	s.shader.SetUniformMat4fv("projection", cam.ProjMatrix())
+
+	s.shader.Unbind()
+
+	return s, nil
+}
+
+// Draws the sprite with a transformation, as seen through the camera
+func (s *Sprite) Draw(cam *Camera, position, rotation mgl32.Vec3, scale float32) {
+	s.bind(cam, modelMatrix(position, rotation, scale))
+
+	s.va.Bind()
+	s.ib.Bind()
+
+	gl.DrawElements(gl.TRIANGLES, s.ib.count, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	//gl.DrawElements(gl.LINES, s.ib.count, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	//gl.PointSize(14)
+	//gl.DrawElements(gl.POINTS, s.ib.count, gl.UNSIGNED_INT, gl.PtrOffset(0))
+
+	s.va.Unbind()
+	s.ib.Unbind()
+	s.shader.Unbind()
+}
+
+// Calculates the model matrix of a transformation
+func modelMatrix(position, rotation mgl32.Vec3, scale float32) mgl32.Mat4 {
+	model := mgl32.Translate3D(position.X(), position.Y(), position.Z())
+	model = model.Mul4(mgl32.HomogRotate3D(float32(rotation.X()), mgl32.Vec3{1, 0, 0}))
+	model = model.Mul4(mgl32.HomogRotate3D(float32(rotation.Y()), mgl32.Vec3{0, 1, 0}))
+	model = model.Mul4(mgl32.HomogRotate3D(float32(rotation.Z()), mgl32.Vec3{0, 0, 1}))
+	model = model.Mul4(mgl32.Scale3D(scale, scale, scale))
+
+	return model
+}
+
+// Binds the shader and textures of the sprite and sets the uniforms that change every frame
+func (s *Sprite) bind(cam *Camera, model mgl32.Mat4) {
+	// Get view matrix from camera
+	view := cam.ViewMatrix()
+
+	// Projection matrix is already set as it does not change
+
+	s.shader.Bind()
+	s.texture.Bind(0)
+	s.normalMap.Bind(1)
+
+	s.shader.SetUniformMat4fv("model", model)
+	s.shader.SetUniformMat4fv("view", view)
+
+	s.shader.SetUniform3f("camPos", cam.GetPosition().X(), cam.GetPosition().Y(), cam.GetPosition().Z())
+}
@@ -1,10 +1,10 @@
-package main
+package renderer
 
 import (
 	"fmt"
 	"image"
 	"image/draw"
-	"os"
+	"path"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 )
@@ -13,32 +13,45 @@ type Texture struct {
 	id uint32
 }
 
+// Creates a new texture and returns it. Panics if something goes wrong while loading the texture.
 func NewTexture(filePath string) Texture {
-	id, err := genTexture(filePath)
+	texture, err := LoadTexture(filePath)
 	if err != nil {
 		panic(err)
 	}
 
-	return Texture{id}
+	return texture
+}
+
+// LoadTexture creates a new texture from a file in the "textures" folder, or returns an error naming the file
+func LoadTexture(filePath string) (Texture, error) {
+	id, err := genTexture(filePath)
+	if err != nil {
+		return Texture{}, err
+	}
+
+	return Texture{id}, nil
 }
 
 func genTexture(filePath string) (uint32, error) {
 	// All textures will be located in the textures folder, so add shortcut
-	filePath = "../res/textures/" + filePath
-	imgFile, err := os.Open(filePath)
+	filePath = path.Join("textures", filePath)
+	imgFile, err := Assets.Open(filePath)
 
 	if err != nil {
 		return 0, fmt.Errorf("texture %q not found on disk: %v", filePath, err)
 	}
+	defer imgFile.Close()
+
 	img, _, err := image.Decode(imgFile)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("failed to decode texture %q: %v", filePath, err)
 	}
 
 	// Generate image data
 	rgba := image.NewRGBA(img.Bounds())
 	if rgba.Stride != rgba.Rect.Size().X*4 {
-		return 0, fmt.Errorf("unsupported stride")
+		return 0, fmt.Errorf("texture %q has an unsupported stride", filePath)
 	}
 	draw.Draw(rgba, rgba.Bounds(), img, image.Point{0, 0}, draw.Src)
 
@@ -60,13 +73,13 @@ func genTexture(filePath string) (uint32, error) {
 }
 
 // Bind texture to texture slot
-func (t *Texture) bind(slot uint32) {
+func (t *Texture) Bind(slot uint32) {
 	gl.ActiveTexture(gl.TEXTURE0 + slot)
 	gl.BindTexture(gl.TEXTURE_2D, t.id)
 }
 
 // Unbind texture slot
-func (t *Texture) unbind(slot uint32) {
+func (t *Texture) Unbind(slot uint32) {
 	gl.ActiveTexture(gl.TEXTURE0 + slot)
 	gl.BindTexture(gl.TEXTURE_2D, 0)
 }
@@ -1,4 +1,4 @@
-package main
+package renderer
 
 import (
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -26,7 +26,7 @@ func NewVertexArray(elementCounts []int) VertexArray {
 	var id uint32
 	gl.GenVertexArrays(1, &id)
 	va := VertexArray{id}
-	va.bind()
+	va.Bind()
 
 	// Calculate size of one vertex
 	stride := 0
@@ -42,19 +42,19 @@ func NewVertexArray(elementCounts []int) VertexArray {
 
 		offset += elementCounts[i] * 4
 	}
-	va.unbind()
+	va.Unbind()
 
 	return va
 }
 
-func (va *VertexArray) delete() {
+func (va *VertexArray) Delete() {
 	gl.DeleteVertexArrays(1, &va.id)
 }
 
-func (va *VertexArray) bind() {
+func (va *VertexArray) Bind() {
 	gl.BindVertexArray(va.id)
 }
 
-func (va *VertexArray) unbind() {
+func (va *VertexArray) Unbind() {
 	gl.BindVertexArray(0)
 }
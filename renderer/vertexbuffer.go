@@ -1,4 +1,4 @@
-package main
+package renderer
 
 import (
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -12,22 +12,22 @@ func NewVertexBuffer(vertices []float32) VertexBuffer {
 	var id uint32
 	gl.GenBuffers(1, &id)
 	vb := VertexBuffer{id}
-	vb.bind()
+	vb.Bind()
 	// Calculate and set size of buffer
 	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
-	vb.unbind()
+	vb.Unbind()
 
 	return vb
 }
 
-func (vb *VertexBuffer) delete() {
+func (vb *VertexBuffer) Delete() {
 	gl.DeleteBuffers(1, &vb.id)
 }
 
-func (vb *VertexBuffer) bind() {
+func (vb *VertexBuffer) Bind() {
 	gl.BindBuffer(gl.ARRAY_BUFFER, vb.id)
 }
 
-func (vb *VertexBuffer) unbind() {
+func (vb *VertexBuffer) Unbind() {
 	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 }
@@ -0,0 +1,65 @@
+// Package res bundles the default shaders, textures, scenes and planet settings into the binary.
+package res
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// FS holds the default assets, laid out like this folder
+//
+//go:embed shaders textures scenes planets
+var FS embed.FS
+
+// EnvVar is the environment variable holding extra asset folders, separated like PATH
+const EnvVar = "PLANET_ASSETS"
+
+// SearchPath is a list of asset roots. Files are opened from the first root that has them.
+type SearchPath []fs.FS
+
+/*
+NewSearchPath creates a search path that looks in every folder in order before falling back to the
+assets bundled into the binary. Each folder is laid out like the res folder, with shaders in
+"shaders" and textures in "textures", and only needs to contain the files it overrides.
+
+Parameters:
+- dirs: the folders to search, earlier folders take priority
+
+Returns:
+- search: the new search path
+
+Example usage:
+
+	renderer.Assets = res.NewSearchPath(filepath.SplitList(os.Getenv(res.EnvVar))...)
+*/
+func NewSearchPath(dirs ...string) SearchPath {
+	search := SearchPath{}
+	for _, dir := range dirs {
+		if dir != "" {
+			search = append(search, os.DirFS(dir))
+		}
+	}
+	return append(search, FS)
+}
+
+// Open opens the named file from the first root that has it
+func (s SearchPath) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	for _, root := range s {
+		file, err := root.Open(name)
+		if err == nil {
+			return file, nil
+		}
+		// Only fall through to the next root if this one does not have the file at all
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
@@ -3,18 +3,32 @@
 
 layout (location = 0) in vec3 aPos;
 layout (location = 1) in vec3 aNormal;
+layout (location = 2) in float aBiome;
+layout (location = 3) in float aIce;
 
 out vec3 VertexPos;
 out vec3 VertexNormal;
 out vec3 FragPos;
 out vec3 Normal;
 out mat4 Model;
+out vec3 BiomeColor;
+out float BiomeWeight;
+out float Ice;
 
 uniform mat4 model;
 uniform mat4 view;
 uniform mat4 projection;
 
+// The colors of the biomes, in the order of generation.Biomes
+uniform vec3 biomeCols[12];
+
 void main() {
+    // Only land biomes change the color, planets without a climate have no biome and the sea floor keeps its colors
+    int biome = int(aBiome + 0.5);
+    BiomeColor = biomeCols[clamp(biome, 0, 11)];
+    BiomeWeight = biome >= 2 ? 1.0 : 0.0;
+    Ice = aIce;
+
     VertexPos = aPos;
     VertexNormal = aNormal;
     Model = model;
@@ -32,6 +46,9 @@ in vec3 VertexNormal;
 in vec3 FragPos;
 in vec3 Normal;
 in mat4 Model;
+in vec3 BiomeColor;
+in float BiomeWeight;
+in float Ice;
 
 layout(location = 0) out vec4 FragColor;
 layout(location = 1) out vec4 DepthColor;
@@ -44,6 +61,7 @@ uniform vec3 flatColLow;
 uniform vec3 flatColHigh;
 uniform vec3 steepColLow;
 uniform vec3 steepColHigh;
+uniform vec3 iceCol;
 
 // Textures
 uniform sampler2D mainTexture;
@@ -161,6 +179,24 @@ vec3 heightColor(vec3 pos) {
     return col;
 }
 
+// Colors flat land by its biome, but leaves shores and steep slopes with their height colors
+vec3 biomeColor(vec3 pos, vec3 col) {
+    float height = length(pos) - 1;
+    float flatness = dot(normalize(VertexNormal), normalize(pos));
+
+    float k = BiomeWeight;
+    k *= clamp((height - 0.01) / (0.02 - 0.01), 0.0, 1.0);
+    k *= clamp((flatness - 0.8) * 10.0, 0.0, 1.0);
+
+    return lerp(col, BiomeColor, k);
+}
+
+// Covers the ground in ice and snow, but steep slopes do not hold snow
+vec3 iceColor(vec3 pos, vec3 col) {
+    float flatness = dot(normalize(VertexNormal), normalize(pos));
+    return lerp(col, iceCol, Ice * clamp((flatness - 0.6) * 5.0, 0.0, 1.0));
+}
+
 void main() {
     // Partially sample texture
     vec3 texColor = vec3(0.7) + triplanarTexture(VertexPos, mainTexture) * 0.3;
@@ -169,6 +205,12 @@ void main() {
     vec3 lightingNormal = triplanarNormal(VertexPos, normalMap);
 
     vec3 heightColor = heightColor(VertexPos);
+    if (BiomeWeight > 0.0) {
+        heightColor = biomeColor(VertexPos, heightColor);
+    }
+    if (Ice > 0.0) {
+        heightColor = iceColor(VertexPos, heightColor);
+    }
 
     // Ambient light: the natural light in space
     float ambientLight = 0.1;
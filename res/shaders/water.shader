@@ -0,0 +1,54 @@
+#shader vertex
+#version 330
+
+layout (location = 0) in vec3 aPos;
+layout (location = 1) in vec3 aNormal;
+
+out vec3 FragPos;
+out vec3 Normal;
+
+uniform mat4 model;
+uniform mat4 view;
+uniform mat4 projection;
+
+void main() {
+    FragPos = vec3(model * vec4(aPos, 1.0));
+    Normal = mat3(transpose(inverse(model))) * aNormal;
+
+    gl_Position = projection * view * vec4(FragPos, 1.0);
+}
+
+#shader fragment
+#version 330
+
+in vec3 FragPos;
+in vec3 Normal;
+
+layout(location = 0) out vec4 FragColor;
+layout(location = 1) out vec4 DepthColor;
+
+uniform vec3 waterCol;
+
+uniform vec3 camPos;
+uniform float camFar;
+
+uniform vec3 lightPos;
+uniform vec3 lightColor;
+
+// Lakes and rivers are lit like the ocean, with a sharp reflection of the sun
+void main() {
+    vec3 normal = normalize(Normal);
+    vec3 lightToFrag = normalize(FragPos - lightPos);
+    vec3 camToFrag = normalize(FragPos - camPos);
+
+    float diffuseLight = clamp(dot(normal, -lightToFrag), 0.0, 0.7);
+
+    vec3 reflection = reflect(lightToFrag, normal);
+    float specularLight = pow(clamp(dot(reflection, -camToFrag), 0.0, 1.0), 32) * 2;
+
+    float ambientLight = 0.1;
+
+    FragColor = vec4(waterCol * (ambientLight + diffuseLight + specularLight) * lightColor, 1.0);
+
+    DepthColor.r = length(FragPos - camPos) / camFar;
+}
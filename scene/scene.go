@@ -0,0 +1,260 @@
+// Package scene reads files that describe whole solar systems.
+package scene
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+	"stensvad-ossianst-melvinbe-project/internal/fileformat"
+)
+
+// MaxAtmospheres is how many planets the atmosphere shader has room for in its uniform block
+const MaxAtmospheres = 10
+
+// Scene describes a whole solar system, see docs/scenes.md for the file format
+type Scene struct {
+	Skybox string `json:"skybox" yaml:"skybox" toml:"skybox"`
+	Camera Camera `json:"camera" yaml:"camera" toml:"camera"`
+	Bodies []Body `json:"bodies" yaml:"bodies" toml:"bodies"`
+}
+
+type Camera struct {
+	Position [3]float32 `json:"position" yaml:"position,flow" toml:"position"`
+}
+
+// Body is a planet, moon or sun in a scene
+type Body struct {
+	Name   string `json:"name" yaml:"name" toml:"name"`
+	Parent string `json:"parent,omitempty" yaml:"parent,omitempty" toml:"parent,omitempty"`
+
+	// The settings of the body are the preset, or the settings file if one is given,
+	// with the inline settings on top
+	Preset       string                       `json:"preset,omitempty" yaml:"preset,omitempty" toml:"preset,omitempty"`
+	SettingsFile string                       `json:"settings_file,omitempty" yaml:"settings_file,omitempty" toml:"settings_file,omitempty"`
+	Settings     generation.SettingsOverrides `json:"settings,omitempty" yaml:"settings,omitempty" toml:"settings,omitempty"`
+	RandomColors bool                         `json:"random_colors,omitempty" yaml:"random_colors,omitempty" toml:"random_colors,omitempty"`
+
+	Orbit Orbit `json:"orbit" yaml:"orbit" toml:"orbit"`
+
+	planetSettings generation.PlanetSettings
+}
+
+type Orbit struct {
+	Distance float32    `json:"distance" yaml:"distance" toml:"distance"`
+	Axis     [3]float32 `json:"axis" yaml:"axis,flow" toml:"axis"`
+	// Time to complete one orbit, negative periods orbit the other way
+	Period float32 `json:"period" yaml:"period" toml:"period"`
+}
+
+/*
+Load reads and validates a scene from a JSON, YAML or TOML file, picked by file extension.
+Settings files referenced by the scene are read relative to the scene file.
+
+Parameters:
+- path: the scene file to read
+
+Returns:
+- scene: the scene described by the file
+- err: an error naming the offending body and key if the file could not be read or is invalid
+
+Example usage:
+
+	s, err := scene.Load("res/scenes/solar.yaml")
+*/
+func Load(path string) (Scene, error) {
+	// Settings files are relative to the scene file unless they are absolute
+	locate := func(name string) string {
+		if filepath.IsAbs(name) {
+			return name
+		}
+		return filepath.Join(filepath.Dir(path), name)
+	}
+
+	return load(path, os.ReadFile, locate)
+}
+
+/*
+LoadFS is like Load but reads the scene and its settings files from a file system,
+such as the scenes bundled into the binary.
+
+Parameters:
+- fsys: the file system to read from
+- name: the scene file to read
+
+Returns:
+- scene: the scene described by the file
+- err: an error naming the offending body and key if the file could not be read or is invalid
+
+Example usage:
+
+	s, err := scene.LoadFS(res.FS, "scenes/solar.yaml")
+*/
+func LoadFS(fsys fs.FS, name string) (Scene, error) {
+	readFile := func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}
+	locate := func(file string) string {
+		return path.Join(path.Dir(name), file)
+	}
+
+	return load(name, readFile, locate)
+}
+
+// Reads and validates a scene, using readFile for every file and locate to find settings files
+func load(name string, readFile func(name string) ([]byte, error), locate func(file string) string) (Scene, error) {
+	data, err := readFile(name)
+	if err != nil {
+		return Scene{}, err
+	}
+
+	var scene Scene
+	if err := fileformat.Decode(name, data, &scene, true); err != nil {
+		return Scene{}, err
+	}
+
+	if err := scene.resolve(readFile, locate); err != nil {
+		return Scene{}, fmt.Errorf("%s: %v", name, err)
+	}
+
+	return scene, nil
+}
+
+// PlanetSettings returns the settings of the body, resolved from its preset, settings file and inline settings
+func (b *Body) PlanetSettings() generation.PlanetSettings {
+	return b.planetSettings
+}
+
+// Root returns the index of the body without a parent
+func (s *Scene) Root() int {
+	for i, body := range s.Bodies {
+		if body.Parent == "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// Children returns the indices of every body that orbits the named body
+func (s *Scene) Children(name string) []int {
+	children := []int{}
+	for i, body := range s.Bodies {
+		if body.Parent == name {
+			children = append(children, i)
+		}
+	}
+	return children
+}
+
+// Checks the structure of the scene and resolves the planet settings of every body
+func (s *Scene) resolve(readFile func(name string) ([]byte, error), locate func(file string) string) error {
+	if s.Skybox == "" {
+		return fmt.Errorf("skybox: must not be empty")
+	}
+	if len(s.Bodies) == 0 {
+		return fmt.Errorf("bodies: must contain at least one body")
+	}
+
+	indices := map[string]int{}
+	roots := 0
+	atmospheres := 0
+
+	for i := range s.Bodies {
+		body := &s.Bodies[i]
+		key := fmt.Sprintf("bodies[%d]", i)
+
+		if body.Name == "" {
+			return fmt.Errorf("%s.name: must not be empty", key)
+		}
+		if _, ok := indices[body.Name]; ok {
+			return fmt.Errorf("%s.name: %q is used by more than one body", key, body.Name)
+		}
+		indices[body.Name] = i
+		key = fmt.Sprintf("bodies[%d] (%s)", i, body.Name)
+
+		if body.Parent == "" {
+			roots++
+		} else if body.Orbit.Distance <= 0 {
+			return fmt.Errorf("%s.orbit.distance: must be greater than 0", key)
+		} else if mgl32.Vec3(body.Orbit.Axis).Len() == 0 {
+			return fmt.Errorf("%s.orbit.axis: must not be zero", key)
+		} else if body.Orbit.Period == 0 {
+			return fmt.Errorf("%s.orbit.period: must not be zero", key)
+		}
+
+		settings, err := body.resolveSettings(readFile, locate)
+		if err != nil {
+			return fmt.Errorf("%s.settings: %v", key, err)
+		}
+		body.planetSettings = settings
+
+		if settings.HasAtmosphere {
+			atmospheres++
+		}
+	}
+
+	if roots != 1 {
+		return fmt.Errorf("bodies: expected exactly one body without a parent, found %d", roots)
+	}
+	if atmospheres > MaxAtmospheres {
+		return fmt.Errorf("bodies: at most %d bodies can have an atmosphere, found %d", MaxAtmospheres, atmospheres)
+	}
+
+	// Every body must lead up to the root without passing itself
+	for i, body := range s.Bodies {
+		visited := map[string]bool{body.Name: true}
+		for parent := body.Parent; parent != ""; parent = s.Bodies[indices[parent]].Parent {
+			if _, ok := indices[parent]; !ok {
+				return fmt.Errorf("bodies[%d] (%s).parent: no body is named %q", i, body.Name, parent)
+			}
+			if visited[parent] {
+				return fmt.Errorf("bodies[%d] (%s).parent: %q orbits itself", i, body.Name, parent)
+			}
+			visited[parent] = true
+		}
+	}
+
+	return nil
+}
+
+// Returns the planet settings of a body from its preset, settings file and inline settings
+func (b *Body) resolveSettings(readFile func(name string) ([]byte, error), locate func(file string) string) (generation.PlanetSettings, error) {
+	if b.Preset != "" && b.SettingsFile != "" {
+		return generation.PlanetSettings{}, fmt.Errorf("preset and settings_file can not both be given")
+	}
+	if _, ok := b.Settings["preset"]; ok {
+		return generation.PlanetSettings{}, fmt.Errorf("preset: set the preset of the body instead")
+	}
+
+	var base generation.PlanetSettings
+	var err error
+	if b.SettingsFile != "" {
+		name := locate(b.SettingsFile)
+		var data []byte
+		data, err = readFile(name)
+		if err == nil {
+			base, err = generation.ParsePlanetSettings(name, data)
+		}
+	} else {
+		base, err = generation.Preset(b.Preset)
+	}
+	if err != nil {
+		return generation.PlanetSettings{}, err
+	}
+
+	settings, err := b.Settings.Apply(base)
+	if err != nil {
+		return generation.PlanetSettings{}, err
+	}
+
+	if b.RandomColors {
+		settings.Colors = generation.RandomColors()
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,90 @@
+package scene
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"stensvad-ossianst-melvinbe-project/generation"
+	"stensvad-ossianst-melvinbe-project/res"
+)
+
+// A sun with one planet, and a settings file next to the scene
+const validScene = `skybox: skybox3
+bodies:
+  - name: sun
+    preset: sun
+  - name: earth
+    parent: sun
+    settings_file: planet.yaml
+    settings:
+      shape: {radius: 2}
+    orbit: {distance: 40, axis: [0, 1, 0], period: 10}
+`
+
+func loadScene(scene string) (Scene, error) {
+	fsys := fstest.MapFS{
+		"scenes/test.yaml":   {Data: []byte(scene)},
+		"scenes/planet.yaml": {Data: []byte("preset: moon\nshape: {seed: 3}\n")},
+	}
+	return LoadFS(fsys, "scenes/test.yaml")
+}
+
+func TestLoad(t *testing.T) {
+	s, err := loadScene(validScene)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Root() != 0 || len(s.Children("sun")) != 1 || len(s.Children("earth")) != 0 {
+		t.Errorf("sun is body %d with %d children", s.Root(), len(s.Children("sun")))
+	}
+
+	// The settings file is read next to the scene, and the inline settings go on top of it
+	want := generation.DefaultMoon()
+	want.Shape.Seed = 3
+	want.Shape.Radius = 2
+	if settings := s.Bodies[1].PlanetSettings(); !reflect.DeepEqual(settings, want) {
+		t.Errorf("earth has seed %d and radius %v, want the moon with seed 3 and radius 2", settings.Shape.Seed, settings.Shape.Radius)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	sun := "  - name: sun\n    preset: sun\n"
+	cases := []struct {
+		scene string
+		want  string
+	}{
+		{"bodies:\n" + sun, "skybox: must not be empty"},
+		{"skybox: a\nbodies: []\n", "bodies: must contain at least one body"},
+		{"skybox: a\nbodies:\n  - preset: sun\n", "bodies[0].name: must not be empty"},
+		{"skybox: a\nbodies:\n" + sun + sun, "bodies[1].name: \"sun\" is used by more than one body"},
+		{"skybox: a\nbodies:\n" + sun + "  - name: moon\n", "bodies: expected exactly one body without a parent, found 2"},
+		{"skybox: a\nbodies:\n" + sun + "  - name: moon\n    parent: sun\n    orbit: {axis: [0, 1, 0], period: 1}\n", "bodies[1] (moon).orbit.distance"},
+		{"skybox: a\nbodies:\n" + sun + "  - name: moon\n    parent: sun\n    orbit: {distance: 1, period: 1}\n", "bodies[1] (moon).orbit.axis"},
+		{"skybox: a\nbodies:\n" + sun + "  - name: moon\n    parent: sun\n    orbit: {distance: 1, axis: [0, 1, 0]}\n", "bodies[1] (moon).orbit.period"},
+		{"skybox: a\nbodies:\n" + sun + "  - name: moon\n    parent: mars\n    orbit: {distance: 1, axis: [0, 1, 0], period: 1}\n", "bodies[1] (moon).parent: no body is named \"mars\""},
+		{"skybox: a\nbodies:\n" + sun + "  - name: a\n    parent: b\n    orbit: {distance: 1, axis: [0, 1, 0], period: 1}\n  - name: b\n    parent: a\n    orbit: {distance: 1, axis: [0, 1, 0], period: 1}\n", "orbits itself"},
+		{"skybox: a\nbodies:\n  - name: sun\n    preset: sun\n    settings_file: planet.yaml\n", "bodies[0] (sun).settings: preset and settings_file can not both be given"},
+		{"skybox: a\nbodies:\n  - name: sun\n    preset: mars\n", "bodies[0] (sun).settings: unknown preset"},
+		{"skybox: a\nbodies:\n  - name: sun\n    settings: {shape: {radius: -1}}\n", "bodies[0] (sun).settings: shape.radius"},
+		{"skybox: a\nbodies:\n  - name: sun\n    settings_file: missing.yaml\n", "bodies[0] (sun).settings"},
+		{"skybox: a\nplanets: []\n", "planets"},
+	}
+
+	for _, c := range cases {
+		_, err := loadScene(c.scene)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("scene\n%s\nreturned %v, want an error containing %q", c.scene, err, c.want)
+		}
+	}
+}
+
+func TestBundledScenes(t *testing.T) {
+	for _, name := range []string{"scenes/solar.yaml", "scenes/frozen.toml"} {
+		if _, err := LoadFS(res.FS, name); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}